@@ -66,7 +66,16 @@ func init() {
 	}
 
 	// Initialize executor and systemd
-	excutorManager = executor.NewExecutor(configManager.GetDatabases(), destinationManager)
+	excutorManager = executor.NewExecutor(configManager.GetDatabases(), destinationManager, configDir)
+	if ttl := configManager.GetOutbox().TTL; ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error parsing outbox ttl: %v\n", err)
+			os.Exit(1)
+		}
+		excutorManager.SetOutboxTTL(d)
+	}
+	excutorManager.SetArtifactRetention(configManager.GetArtifacts().KeepLast)
 }
 
 func main() {
@@ -100,6 +109,8 @@ func rootCommand() error {
 		return handleLogCommand(os.Args[2:])
 	case "debug":
 		return handleDebugCommand(os.Args[2:])
+	case "outbox":
+		return handleOutboxCommand(os.Args[2:])
 	default:
 		return fmt.Errorf("unknown command: %s", os.Args[1])
 	}
@@ -107,7 +118,7 @@ func rootCommand() error {
 
 func handleTaskCommand(args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("usage: goractor task [list|show|add|remove|edit|run] [task-name]")
+		return fmt.Errorf("usage: goractor task [list|show|add|remove|edit|run|history|resend] [task-name]")
 	}
 
 	switch args[0] {
@@ -135,6 +146,17 @@ func handleTaskCommand(args []string) error {
 			return fmt.Errorf("usage: goractor task run [task-name]")
 		}
 		return runTask(args[1])
+	case "history":
+		switch len(args) {
+		case 2:
+			return listRuns(args[1])
+		case 3:
+			return showRun(args[1], args[2])
+		default:
+			return fmt.Errorf("usage: goractor task history [task-name] [run-id]")
+		}
+	case "resend":
+		return resendTask(args[1:])
 	default:
 		return fmt.Errorf("unknown task command: %s", args[0])
 	}
@@ -167,7 +189,12 @@ func handleConfigCommand(args []string) error {
 			return fmt.Errorf("database %s not found", args[1])
 		}
 		dbCopy := *db
-		dbCopy.Password = "********"
+		if dbCopy.Password != "" {
+			dbCopy.Password = "********"
+		}
+		if dbCopy.DSN != "" {
+			dbCopy.DSN = "********"
+		}
 		data, _ := yaml.Marshal(dbCopy)
 		fmt.Printf("Database: %s\n%s", args[1], string(data))
 		return nil
@@ -237,8 +264,7 @@ func handleDestinationCommand(args []string) error {
 			return fmt.Errorf("destination %s not found", args[1])
 		}
 		// Hide sensitive values
-		dest.Token.Value = "********"
-		data, _ := yaml.Marshal(dest)
+		data, _ := yaml.Marshal(dest.Redacted())
 		fmt.Printf("Destination: %s\n%s", args[1], string(data))
 		return nil
 
@@ -361,6 +387,72 @@ func handleLogCommand(args []string) error {
 	}
 }
 
+func handleOutboxCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: goractor outbox [list|retry|drop] [item-id|--due]")
+	}
+
+	switch args[0] {
+	case "list":
+		return listOutbox()
+	case "retry":
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		// --due is used by the outbox timer and respects each item's backoff
+		if len(args) == 2 && args[1] == "--due" {
+			return excutorManager.RetryOutbox(ctx, "", false)
+		}
+		if len(args) == 2 {
+			if err := excutorManager.RetryOutboxItem(ctx, args[1]); err != nil {
+				return err
+			}
+			fmt.Printf("Delivered %s\n", args[1])
+			return nil
+		}
+		return excutorManager.RetryOutbox(ctx, "", true)
+	case "drop":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: goractor outbox drop [item-id|all]")
+		}
+		if args[1] == "all" {
+			items, err := excutorManager.ListOutbox()
+			if err != nil {
+				return err
+			}
+			if len(items) == 0 || !confirmPrompt(fmt.Sprintf("Drop %d undelivered results", len(items))) {
+				return nil
+			}
+			for _, item := range items {
+				if err := excutorManager.DropOutboxItem(item.ID); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return excutorManager.DropOutboxItem(args[1])
+	default:
+		return fmt.Errorf("unknown outbox command: %s", args[0])
+	}
+}
+
+func listOutbox() error {
+	items, err := excutorManager.ListOutbox()
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		fmt.Println("Outbox is empty")
+		return nil
+	}
+
+	fmt.Printf("%-40s %-20s %8s  %-20s %s\n", "ID", "DESTINATION", "ATTEMPTS", "QUEUED", "LAST ERROR")
+	for _, item := range items {
+		fmt.Printf("%-40s %-20s %8d  %-20s %s\n", item.ID, item.Destination, item.Attempts,
+			item.CreatedAt.Format("2006-01-02 15:04:05"), item.LastError)
+	}
+	return nil
+}
+
 func handleDebugCommand(args []string) error {
 	if len(args) == 0 {
 		return fmt.Errorf("usage: goractor debug [task-name]")
@@ -613,6 +705,77 @@ func runTask(name string) error {
 	return nil
 }
 
+func listRuns(name string) error {
+	runs, err := excutorManager.ListRuns(name)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		fmt.Printf("No runs recorded for task %s\n", name)
+		return nil
+	}
+
+	fmt.Printf("%-24s %-8s %6s  %-20s %s\n", "RUN ID", "STATUS", "ROWS", "DESTINATION", "RECEIPT / ERROR")
+	for _, run := range runs {
+		detail := run.ReceiptID
+		if run.Error != "" {
+			detail = run.Error
+		}
+		fmt.Printf("%-24s %-8s %6d  %-20s %s\n", run.ID, run.Status, run.RowCount, run.Destination, detail)
+	}
+	return nil
+}
+
+func showRun(name, id string) error {
+	run, err := excutorManager.GetRun(name, id)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("failed to format run: %w", err)
+	}
+	fmt.Print(string(data))
+	return nil
+}
+
+func resendTask(args []string) error {
+	usage := fmt.Errorf("usage: goractor task resend [task-name] [run-id] [--destination name] [--mentions]")
+
+	var positional []string
+	destinationName := ""
+	mentions := false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--mentions":
+			mentions = true
+		case args[i] == "--destination":
+			if i+1 >= len(args) {
+				return usage
+			}
+			destinationName = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--destination="):
+			destinationName = strings.TrimPrefix(args[i], "--destination=")
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+	if len(positional) != 2 {
+		return usage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := excutorManager.Resend(ctx, positional[0], positional[1], destinationName, mentions); err != nil {
+		return err
+	}
+	fmt.Printf("Resent result of run %s\n", positional[1])
+	return nil
+}
+
 func installTask(name string) error {
 	task, err := taskManager.Get(name)
 	if err != nil {
@@ -626,6 +789,7 @@ func installTask(name string) error {
 	}
 
 	generator := systemd.NewServiceGenerator()
+	generator.SetEnvironmentFile(configManager.GetSystemd().EnvironmentFile)
 	if err := generator.GenerateService(&task); err != nil {
 		return err
 	}
@@ -703,7 +867,7 @@ Goractor - PostgreSQL Task Scheduler
 What you can do:
 • Schedule PostgreSQL queries to run automatically
 • Send results to Slack or other API endpoints
-• Export query results as CSV
+• Export query results as CSV or Excel (XLSX)
 • Manage multiple database connections
 • Monitor and debug task execution
 
@@ -718,7 +882,7 @@ config        Configure database connections
 	show         Display database details
 
 destination   Set up where to send results
-	add          Add new destination (Slack/API)
+	add          Add new destination (Slack/Teams/Discord/API)
 	list         Show configured destinations
 	remove       Remove a destination
 	show         Display destination details
@@ -729,6 +893,8 @@ task          Manage scheduled tasks
 	remove       Remove a task
 	show         Display task details
 	run          Test task execution
+	history      Show recorded runs and delivery receipts
+	resend       Deliver a stored result again (--destination to redirect, --mentions to notify)
 
 systemd       Control task scheduling
 	install      Set up task schedule
@@ -736,6 +902,11 @@ systemd       Control task scheduling
 	disable      Stop task execution
 	status       Check scheduler status
 
+outbox        Manage results that could not be delivered
+	list         Show undelivered results
+	retry        Deliver all now, only due items (--due), or a single item by ID
+	drop         Remove an item by ID, or all
+
 debug         Troubleshoot task issues
 log           View or clear execution logs
 
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+)
+
+const defaultPort = 5432
+
+// ConnectionString returns the libpq connection string for the database.
+// A configured DSN is used as is; otherwise the string is built from the
+// individual fields, quoting values so any character is allowed.
+func (c *DBConfig) ConnectionString() string {
+	if c.DSN != "" {
+		return c.DSN
+	}
+
+	port := c.Port
+	if port == 0 {
+		port = defaultPort
+	}
+
+	params := []struct{ key, value string }{
+		{"host", c.Host},
+		{"port", strconv.Itoa(port)},
+		{"user", c.User},
+		{"password", c.Password},
+		{"dbname", c.DBName},
+		{"sslmode", c.GetSSLMode()},
+		{"sslrootcert", c.SSLRootCert},
+		{"sslcert", c.SSLCert},
+		{"sslkey", c.SSLKey},
+	}
+
+	parts := make([]string, 0, len(params))
+	for _, param := range params {
+		if param.value == "" {
+			continue
+		}
+		parts = append(parts, param.key+"="+quoteConnValue(param.value))
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteConnValue quotes a value for a key/value connection string
+func quoteConnValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestConnectionString(t *testing.T) {
+	tests := []struct {
+		name string
+		db   DBConfig
+		want string
+	}{
+		{
+			name: "dsn is used as is",
+			db:   DBConfig{DSN: "postgres://u:p@db/app?sslmode=require", Host: "ignored"},
+			want: "postgres://u:p@db/app?sslmode=require",
+		},
+		{
+			name: "defaults",
+			db:   DBConfig{Host: "localhost", User: "app", DBName: "reports"},
+			want: "host='localhost' port='5432' user='app' dbname='reports' sslmode='disable'",
+		},
+		{
+			name: "special characters are quoted",
+			db:   DBConfig{Host: "db", Port: 6432, User: "app", Password: `p@ss w'rd\=`, DBName: "my db"},
+			want: `host='db' port='6432' user='app' password='p@ss w\'rd\\=' dbname='my db' sslmode='disable'`,
+		},
+		{
+			name: "ssl files",
+			db: DBConfig{
+				Host: "db", User: "app", DBName: "reports", SSLMode: "verify-full",
+				SSLRootCert: "/etc/ssl/ca.pem", SSLCert: "/etc/ssl/client.pem", SSLKey: "/etc/ssl/client.key",
+			},
+			want: "host='db' port='5432' user='app' dbname='reports' sslmode='verify-full' " +
+				"sslrootcert='/etc/ssl/ca.pem' sslcert='/etc/ssl/client.pem' sslkey='/etc/ssl/client.key'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.db.ConnectionString()
+			if got != tt.want {
+				t.Errorf("ConnectionString() = %s, want %s", got, tt.want)
+			}
+			if tt.db.DSN == "" {
+				if _, err := pq.NewConnector(got); err != nil {
+					t.Errorf("pq rejected %s: %v", got, err)
+				}
+			}
+		})
+	}
+}
+
+func TestQuoteConnValue(t *testing.T) {
+	tests := map[string]string{
+		"":           "''",
+		"plain":      "'plain'",
+		"with space": "'with space'",
+		`it's`:       `'it\'s'`,
+		`back\slash`: `'back\\slash'`,
+		`\'`:         `'\\\''`,
+	}
+	for value, want := range tests {
+		if got := quoteConnValue(value); got != want {
+			t.Errorf("quoteConnValue(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
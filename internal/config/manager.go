@@ -5,12 +5,14 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/ONCALLJP/goractor/internal/secret"
 	"gopkg.in/yaml.v3"
 )
 
 type Manager struct {
 	configPath string
 	config     *Config
+	secrets    *secret.Values // secret references resolved by Load
 }
 
 func NewManager(configPath string) *Manager {
@@ -36,6 +38,9 @@ func (m *Manager) Load() error {
 		return fmt.Errorf("error parsing config: %w", err)
 	}
 
+	// Unavailable secrets are reported when a task uses the database
+	m.secrets = secret.Resolve(m.config)
+
 	return nil
 }
 
@@ -45,7 +50,7 @@ func (m *Manager) Save() error {
 		return fmt.Errorf("error creating config directory: %w", err)
 	}
 
-	data, err := yaml.Marshal(m.config)
+	data, err := secret.MarshalYAML(m.config, m.secrets)
 	if err != nil {
 		return fmt.Errorf("error marshaling config: %w", err)
 	}
@@ -74,6 +79,18 @@ func (m *Manager) GetDatabases() map[string]*DBConfig {
 	return m.config.Databases
 }
 
+func (m *Manager) GetOutbox() OutboxConfig {
+	return m.config.Outbox
+}
+
+func (m *Manager) GetArtifacts() ArtifactsConfig {
+	return m.config.Artifacts
+}
+
+func (m *Manager) GetSystemd() SystemdConfig {
+	return m.config.Systemd
+}
+
 func (m *Manager) AddDatabase(name string, config *DBConfig) error {
 	if _, exists := m.config.Databases[name]; exists {
 		return fmt.Errorf("database %s already exists", name)
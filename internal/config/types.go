@@ -1,13 +1,47 @@
 package config
 
 type DBConfig struct {
-	Host     string `yaml:"host"`
-	Port     int    `yaml:"port"`
-	User     string `yaml:"user"`
-	Password string `yaml:"password"`
-	DBName   string `yaml:"dbname"`
+	DSN         string `yaml:"dsn,omitempty"` // raw connection string or postgres:// URI, overrides the fields below
+	Host        string `yaml:"host,omitempty"`
+	Port        int    `yaml:"port,omitempty"`
+	User        string `yaml:"user,omitempty"`
+	Password    string `yaml:"password,omitempty"`
+	DBName      string `yaml:"dbname,omitempty"`
+	SSLMode     string `yaml:"sslmode,omitempty"`     // disable (default), require, verify-ca or verify-full
+	SSLRootCert string `yaml:"sslrootcert,omitempty"` // CA certificate for verify-ca/verify-full
+	SSLCert     string `yaml:"sslcert,omitempty"`     // client certificate
+	SSLKey      string `yaml:"sslkey,omitempty"`      // client key
+}
+
+// SSLModes lists the supported values of DBConfig.SSLMode
+var SSLModes = []string{"disable", "require", "verify-ca", "verify-full"}
+
+// GetSSLMode returns the configured SSL mode, defaulting to disable
+func (c *DBConfig) GetSSLMode() string {
+	if c.SSLMode == "" {
+		return "disable"
+	}
+	return c.SSLMode
 }
 
 type Config struct {
 	Databases map[string]*DBConfig `yaml:"databases"`
+	Outbox    OutboxConfig         `yaml:"outbox,omitempty"`
+	Artifacts ArtifactsConfig      `yaml:"artifacts,omitempty"`
+	Systemd   SystemdConfig        `yaml:"systemd,omitempty"`
+}
+
+// OutboxConfig controls how long undelivered results are kept for redelivery
+type OutboxConfig struct {
+	TTL string `yaml:"ttl,omitempty"` // e.g. "72h" (default), "30m"
+}
+
+// SystemdConfig is applied to the units written by "systemd install"
+type SystemdConfig struct {
+	EnvironmentFile string `yaml:"environment_file,omitempty"` // e.g. /etc/goractor/secrets.env, for ${env:...} references
+}
+
+// ArtifactsConfig controls how many rendered results are kept for resending
+type ArtifactsConfig struct {
+	KeepLast int `yaml:"keep_last,omitempty"` // per task, 0 (default) keeps none
 }
@@ -5,12 +5,14 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/ONCALLJP/goractor/internal/secret"
 	"gopkg.in/yaml.v3"
 )
 
 type Manager struct {
 	configPath   string
 	destinations map[string]Destination
+	secrets      *secret.Values // secret references resolved by Load
 }
 
 func NewManager(configPath string) *Manager {
@@ -33,6 +35,9 @@ func (m *Manager) Load() error {
 		return fmt.Errorf("error parsing destinations: %w", err)
 	}
 
+	// Unavailable secrets are reported when a task delivers to the destination
+	m.secrets = secret.Resolve(&m.destinations)
+
 	return nil
 }
 
@@ -42,7 +47,7 @@ func (m *Manager) Save() error {
 		return fmt.Errorf("error creating config directory: %w", err)
 	}
 
-	data, err := yaml.Marshal(m.destinations)
+	data, err := secret.MarshalYAML(&m.destinations, m.secrets)
 	if err != nil {
 		return fmt.Errorf("error marshaling destinations: %w", err)
 	}
@@ -3,6 +3,7 @@ package destination
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/manifoldco/promptui"
@@ -29,7 +30,7 @@ func (p *Prompt) PromptDestination(defaultDest *Destination) (string, Destinatio
 	// Destination type
 	typePrompt := promptui.Select{
 		Label: "Destination Type",
-		Items: []string{"slack", "lineworks", "custom"},
+		Items: []string{"slack", "lineworks", "teams", "discord", "s3", "sftp", "file", "postgres", "kafka", "spreadsheet", "custom"},
 	}
 	_, destType, err := typePrompt.Run()
 	if err != nil {
@@ -38,6 +39,11 @@ func (p *Prompt) PromptDestination(defaultDest *Destination) (string, Destinatio
 
 	var dest Destination
 	dest.Type = destType
+	// Headers, TLS and proxy settings are edited in destinations.yaml or the
+	// network prompt below, keep them when editing
+	if defaultDest != nil && defaultDest.Type == destType {
+		dest.HTTP = defaultDest.HTTP
+	}
 
 	// Get the rest of the configuration based on type
 	switch destType {
@@ -51,22 +57,110 @@ func (p *Prompt) PromptDestination(defaultDest *Destination) (string, Destinatio
 			return "", Destination{}, err
 		}
 
+	case "teams":
+		if err := p.promptTeamsConfig(&dest, defaultDest); err != nil {
+			return "", Destination{}, err
+		}
+
+	case "discord":
+		if err := p.promptDiscordConfig(&dest, defaultDest); err != nil {
+			return "", Destination{}, err
+		}
+
+	case "s3":
+		if err := p.promptS3Config(&dest, defaultDest); err != nil {
+			return "", Destination{}, err
+		}
+
+	case "sftp":
+		if err := p.promptSFTPConfig(&dest, defaultDest); err != nil {
+			return "", Destination{}, err
+		}
+
+	case "file":
+		if err := p.promptFileConfig(&dest, defaultDest); err != nil {
+			return "", Destination{}, err
+		}
+
+	case "postgres":
+		if err := p.promptPostgresConfig(&dest, defaultDest); err != nil {
+			return "", Destination{}, err
+		}
+
+	case "kafka":
+		if err := p.promptKafkaConfig(&dest, defaultDest); err != nil {
+			return "", Destination{}, err
+		}
+
+	case "spreadsheet":
+		if err := p.promptSpreadsheetConfig(&dest, defaultDest); err != nil {
+			return "", Destination{}, err
+		}
+
 	case "custom":
 		if err := p.promptCustomConfig(&dest, defaultDest); err != nil {
 			return "", Destination{}, err
 		}
 	}
 
+	if usesHTTPClient(dest) {
+		networkPrompt := promptui.Select{
+			Label: "TLS / Proxy Settings",
+			Items: []string{"default", "configure"},
+		}
+		idx, _, err := networkPrompt.Run()
+		if err != nil {
+			return "", Destination{}, fmt.Errorf("network settings prompt failed: %w", err)
+		}
+		if idx == 1 {
+			if err := p.promptNetworkConfig(&dest); err != nil {
+				return "", Destination{}, err
+			}
+		}
+	}
+
 	return name, dest, nil
 }
 
+// usesHTTPClient reports whether the destination is delivered over HTTP with
+// its TLS and proxy settings applied
+func usesHTTPClient(dest Destination) bool {
+	switch dest.Type {
+	case "teams", "discord", "spreadsheet", "custom":
+		return true
+	case "slack":
+		return dest.Token.Type == "webhook"
+	}
+	return false
+}
+
 func (p *Prompt) promptSlackConfig(dest *Destination, defaultDest *Destination) error {
+	connectionPrompt := promptui.Select{
+		Label: "Slack Connection",
+		Items: []string{"bot", "webhook"},
+	}
+	_, connection, err := connectionPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("connection prompt failed: %w", err)
+	}
+
+	if connection == "webhook" {
+		return p.promptSlackWebhookConfig(dest, defaultDest)
+	}
+	return p.promptSlackBotConfig(dest, defaultDest)
+}
+
+func (p *Prompt) promptSlackBotConfig(dest *Destination, defaultDest *Destination) error {
 	// Get default values
 	defaultToken := ""
 	defaultChannel := ""
-	if defaultDest != nil && defaultDest.Type == "slack" {
+	defaultMaxRows := "20"
+	if defaultDest != nil && defaultDest.Type == "slack" && defaultDest.Token.Type == "bot" {
 		defaultToken = defaultDest.Token.Value
 		defaultChannel = defaultDest.Channel
+		if defaultDest.Slack.MaxTableRows > 0 {
+			defaultMaxRows = strconv.Itoa(defaultDest.Slack.MaxTableRows)
+		}
 	}
 
 	// Token
@@ -99,6 +193,90 @@ func (p *Prompt) promptSlackConfig(dest *Destination, defaultDest *Destination)
 	}
 	dest.Channel = channel
 
+	// Delivery
+	deliveryPrompt := promptui.Select{
+		Label: "Delivery",
+		Items: []string{"file", "table"},
+	}
+	_, delivery, err := deliveryPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("delivery prompt failed: %w", err)
+	}
+
+	if delivery == "table" {
+		maxRowsPrompt := promptui.Prompt{
+			Label:     "Max rows to post as a table (larger results are uploaded as a file)",
+			Validate:  validatePositiveInt,
+			AllowEdit: true,
+			Default:   defaultMaxRows,
+		}
+		maxRows, err := maxRowsPrompt.Run()
+		if err != nil {
+			return fmt.Errorf("max rows prompt failed: %w", err)
+		}
+		dest.Slack.Delivery = delivery
+		dest.Slack.MaxTableRows, _ = strconv.Atoi(maxRows)
+	}
+
+	return nil
+}
+
+func (p *Prompt) promptSlackWebhookConfig(dest *Destination, defaultDest *Destination) error {
+	// Get default values
+	defaultURL := ""
+	defaultLinkURL := ""
+	defaultMaxRows := "20"
+	if defaultDest != nil && defaultDest.Type == "slack" && defaultDest.Token.Type == "webhook" {
+		defaultURL = defaultDest.Token.Value
+		defaultLinkURL = defaultDest.LinkURL
+		if defaultDest.Slack.MaxTableRows > 0 {
+			defaultMaxRows = strconv.Itoa(defaultDest.Slack.MaxTableRows)
+		}
+	}
+
+	// Webhook URL
+	urlPrompt := promptui.Prompt{
+		Label:     "Slack Webhook URL (https://hooks.slack.com/...)",
+		Validate:  validateURL,
+		Mask:      '*',
+		AllowEdit: true,
+		Default:   defaultURL,
+	}
+	url, err := urlPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("webhook URL prompt failed: %w", err)
+	}
+	dest.Token = TokenConfig{
+		Type:  "webhook",
+		Value: url,
+	}
+
+	// Webhooks cannot upload files, so results are always posted inline
+	maxRowsPrompt := promptui.Prompt{
+		Label:     "Max rows to post as a table (larger results only show a summary)",
+		Validate:  validatePositiveInt,
+		AllowEdit: true,
+		Default:   defaultMaxRows,
+	}
+	maxRows, err := maxRowsPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("max rows prompt failed: %w", err)
+	}
+	dest.Slack.MaxTableRows, _ = strconv.Atoi(maxRows)
+
+	// Result link
+	linkPrompt := promptui.Prompt{
+		Label:     "Result Link (optional, e.g. https://reports.example.com/{{task}}/{{date}}.csv)",
+		Validate:  validateOptionalURL,
+		AllowEdit: true,
+		Default:   defaultLinkURL,
+	}
+	linkURL, err := linkPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("result link prompt failed: %w", err)
+	}
+	dest.LinkURL = strings.TrimSpace(linkURL)
+
 	return nil
 }
 
@@ -140,6 +318,700 @@ func (p *Prompt) promptLineworksConfig(dest *Destination, defaultDest *Destinati
 	return nil
 }
 
+func (p *Prompt) promptTeamsConfig(dest *Destination, defaultDest *Destination) error {
+	// Get default values
+	defaultURL := ""
+	defaultLinkURL := ""
+	defaultMaxRows := "10"
+	if defaultDest != nil && defaultDest.Type == "teams" {
+		defaultURL = defaultDest.URL
+		defaultLinkURL = defaultDest.LinkURL
+		if defaultDest.Teams.MaxTableRows > 0 {
+			defaultMaxRows = strconv.Itoa(defaultDest.Teams.MaxTableRows)
+		}
+	}
+
+	// Webhook URL
+	urlPrompt := promptui.Prompt{
+		Label:     "Teams Webhook URL",
+		Validate:  validateURL,
+		AllowEdit: true,
+		Default:   defaultURL,
+	}
+	url, err := urlPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("webhook URL prompt failed: %w", err)
+	}
+	dest.URL = url
+
+	// Table size
+	maxRowsPrompt := promptui.Prompt{
+		Label:     "Max rows to show in the card",
+		Validate:  validatePositiveInt,
+		AllowEdit: true,
+		Default:   defaultMaxRows,
+	}
+	maxRows, err := maxRowsPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("max rows prompt failed: %w", err)
+	}
+	dest.Teams.MaxTableRows, _ = strconv.Atoi(maxRows)
+
+	// Result link
+	linkPrompt := promptui.Prompt{
+		Label:     "Result Link (optional, e.g. https://reports.example.com/{{task}}/{{date}}.csv)",
+		Validate:  validateOptionalURL,
+		AllowEdit: true,
+		Default:   defaultLinkURL,
+	}
+	linkURL, err := linkPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("result link prompt failed: %w", err)
+	}
+	dest.LinkURL = strings.TrimSpace(linkURL)
+
+	return nil
+}
+
+func (p *Prompt) promptDiscordConfig(dest *Destination, defaultDest *Destination) error {
+	// Get default values
+	defaultURL := ""
+	if defaultDest != nil && defaultDest.Type == "discord" {
+		defaultURL = defaultDest.URL
+	}
+
+	// Webhook URL
+	urlPrompt := promptui.Prompt{
+		Label:     "Discord Webhook URL (https://discord.com/api/webhooks/...)",
+		Validate:  validateURL,
+		AllowEdit: true,
+		Default:   defaultURL,
+	}
+	url, err := urlPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("webhook URL prompt failed: %w", err)
+	}
+	dest.URL = url
+
+	return nil
+}
+
+func (p *Prompt) promptS3Config(dest *Destination, defaultDest *Destination) error {
+	// Get default values
+	defaults := S3Options{
+		Endpoint:    "https://s3.amazonaws.com",
+		KeyTemplate: "reports/{{task}}/{{date}}/{{task}}_{{timestamp}}.{{ext}}",
+	}
+	if defaultDest != nil && defaultDest.Type == "s3" {
+		defaults = defaultDest.S3
+	}
+
+	// Endpoint
+	endpointPrompt := promptui.Prompt{
+		Label:     "Endpoint (e.g. https://s3.amazonaws.com, http://localhost:9000)",
+		Validate:  validateURL,
+		AllowEdit: true,
+		Default:   defaults.Endpoint,
+	}
+	endpoint, err := endpointPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("endpoint prompt failed: %w", err)
+	}
+	dest.S3.Endpoint = endpoint
+
+	// Region
+	regionPrompt := promptui.Prompt{
+		Label:     "Region (optional, e.g. ap-northeast-1)",
+		AllowEdit: true,
+		Default:   defaults.Region,
+	}
+	region, err := regionPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("region prompt failed: %w", err)
+	}
+	dest.S3.Region = strings.TrimSpace(region)
+
+	// Bucket
+	bucketPrompt := promptui.Prompt{
+		Label:     "Bucket",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.Bucket,
+	}
+	bucket, err := bucketPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("bucket prompt failed: %w", err)
+	}
+	dest.S3.Bucket = bucket
+
+	// Addressing
+	stylePrompt := promptui.Select{
+		Label: "Bucket Addressing",
+		Items: []string{"auto", "path style (MinIO)"},
+	}
+	idx, _, err := stylePrompt.Run()
+	if err != nil {
+		return fmt.Errorf("bucket addressing prompt failed: %w", err)
+	}
+	dest.S3.PathStyle = idx == 1
+
+	// Credentials
+	accessKeyPrompt := promptui.Prompt{
+		Label:     "Access Key ID (empty to use AWS environment/instance credentials)",
+		AllowEdit: true,
+		Default:   defaults.AccessKeyID,
+	}
+	accessKey, err := accessKeyPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("access key prompt failed: %w", err)
+	}
+	dest.S3.AccessKeyID = strings.TrimSpace(accessKey)
+
+	if dest.S3.AccessKeyID != "" {
+		secretKeyPrompt := promptui.Prompt{
+			Label:     "Secret Access Key",
+			Validate:  validateNotEmpty,
+			Mask:      '*',
+			AllowEdit: true,
+			Default:   defaults.SecretAccessKey,
+		}
+		secretKey, err := secretKeyPrompt.Run()
+		if err != nil {
+			return fmt.Errorf("secret key prompt failed: %w", err)
+		}
+		dest.S3.SecretAccessKey = secretKey
+	}
+
+	// Object key
+	keyPrompt := promptui.Prompt{
+		Label:     "Key Template ({{task}}, {{date}}, {{timestamp}}, {{ext}})",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.KeyTemplate,
+	}
+	keyTemplate, err := keyPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("key template prompt failed: %w", err)
+	}
+	dest.S3.KeyTemplate = keyTemplate
+
+	return nil
+}
+
+func (p *Prompt) promptSFTPConfig(dest *Destination, defaultDest *Destination) error {
+	// Get default values
+	defaults := SFTPOptions{
+		Port:             22,
+		KnownHostsPath:   "~/.ssh/known_hosts",
+		FilenameTemplate: "{{task}}_{{timestamp}}.{{ext}}",
+	}
+	if defaultDest != nil && defaultDest.Type == "sftp" {
+		defaults = defaultDest.SFTP
+	}
+
+	// Host
+	hostPrompt := promptui.Prompt{
+		Label:     "Host",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.Host,
+	}
+	host, err := hostPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("host prompt failed: %w", err)
+	}
+	dest.SFTP.Host = host
+
+	// Port
+	portPrompt := promptui.Prompt{
+		Label:     "Port",
+		Validate:  validatePositiveInt,
+		AllowEdit: true,
+		Default:   strconv.Itoa(defaults.Port),
+	}
+	port, err := portPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("port prompt failed: %w", err)
+	}
+	dest.SFTP.Port, _ = strconv.Atoi(port)
+
+	// User
+	userPrompt := promptui.Prompt{
+		Label:     "User",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.User,
+	}
+	user, err := userPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("user prompt failed: %w", err)
+	}
+	dest.SFTP.User = user
+
+	// Authentication
+	authPrompt := promptui.Select{
+		Label: "Authentication",
+		Items: []string{"private key", "password"},
+	}
+	_, auth, err := authPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("auth type prompt failed: %w", err)
+	}
+
+	if auth == "password" {
+		passwordPrompt := promptui.Prompt{
+			Label:     "Password",
+			Validate:  validateNotEmpty,
+			Mask:      '*',
+			AllowEdit: true,
+			Default:   defaults.Password,
+		}
+		password, err := passwordPrompt.Run()
+		if err != nil {
+			return fmt.Errorf("password prompt failed: %w", err)
+		}
+		dest.SFTP.Password = password
+	} else {
+		keyPrompt := promptui.Prompt{
+			Label:     "Private Key Path",
+			Validate:  validateNotEmpty,
+			AllowEdit: true,
+			Default:   defaults.PrivateKeyPath,
+		}
+		keyPath, err := keyPrompt.Run()
+		if err != nil {
+			return fmt.Errorf("private key prompt failed: %w", err)
+		}
+		dest.SFTP.PrivateKeyPath = keyPath
+
+		passphrasePrompt := promptui.Prompt{
+			Label:     "Key Passphrase (empty if none)",
+			Mask:      '*',
+			AllowEdit: true,
+			Default:   defaults.Passphrase,
+		}
+		passphrase, err := passphrasePrompt.Run()
+		if err != nil {
+			return fmt.Errorf("passphrase prompt failed: %w", err)
+		}
+		dest.SFTP.Passphrase = passphrase
+	}
+
+	// Host key verification
+	knownHostsPrompt := promptui.Prompt{
+		Label:     "known_hosts Path",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.KnownHostsPath,
+	}
+	knownHosts, err := knownHostsPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("known_hosts prompt failed: %w", err)
+	}
+	dest.SFTP.KnownHostsPath = knownHosts
+
+	// Remote location
+	dirPrompt := promptui.Prompt{
+		Label:     "Remote Directory",
+		AllowEdit: true,
+		Default:   defaults.RemoteDir,
+	}
+	remoteDir, err := dirPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("remote directory prompt failed: %w", err)
+	}
+	dest.SFTP.RemoteDir = strings.TrimSpace(remoteDir)
+
+	filenamePrompt := promptui.Prompt{
+		Label:     "Filename Template ({{task}}, {{date}}, {{timestamp}}, {{ext}})",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.FilenameTemplate,
+	}
+	filename, err := filenamePrompt.Run()
+	if err != nil {
+		return fmt.Errorf("filename template prompt failed: %w", err)
+	}
+	dest.SFTP.FilenameTemplate = filename
+
+	return nil
+}
+
+func (p *Prompt) promptFileConfig(dest *Destination, defaultDest *Destination) error {
+	// Get default values
+	defaults := FileOptions{
+		Directory:        "~/goractor-results",
+		FilenameTemplate: "{{task}}_{{timestamp}}.{{ext}}",
+	}
+	if defaultDest != nil && defaultDest.Type == "file" {
+		defaults = defaultDest.File
+	}
+
+	// Directory
+	dirPrompt := promptui.Prompt{
+		Label:     "Directory",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.Directory,
+	}
+	dir, err := dirPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("directory prompt failed: %w", err)
+	}
+	dest.File.Directory = dir
+
+	// Filename
+	filenamePrompt := promptui.Prompt{
+		Label:     "Filename Template ({{task}}, {{date}}, {{timestamp}}, {{ext}})",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.FilenameTemplate,
+	}
+	filename, err := filenamePrompt.Run()
+	if err != nil {
+		return fmt.Errorf("filename template prompt failed: %w", err)
+	}
+	dest.File.FilenameTemplate = filename
+
+	// Partitioning
+	partitionPrompt := promptui.Select{
+		Label: "Subdirectories",
+		Items: []string{"none", "daily (YYYY/MM/DD)", "monthly (YYYY/MM)"},
+	}
+	idx, _, err := partitionPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("subdirectory prompt failed: %w", err)
+	}
+	dest.File.Partition = []string{"", "daily", "monthly"}[idx]
+
+	// Retention
+	keepLastPrompt := promptui.Prompt{
+		Label:     "Keep last N files (0 to keep all)",
+		Validate:  validateNonNegativeInt,
+		AllowEdit: true,
+		Default:   strconv.Itoa(defaults.KeepLast),
+	}
+	keepLast, err := keepLastPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("retention prompt failed: %w", err)
+	}
+	dest.File.KeepLast, _ = strconv.Atoi(keepLast)
+
+	keepDaysPrompt := promptui.Prompt{
+		Label:     "Keep files for N days (0 to keep forever)",
+		Validate:  validateNonNegativeInt,
+		AllowEdit: true,
+		Default:   strconv.Itoa(defaults.KeepDays),
+	}
+	keepDays, err := keepDaysPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("retention prompt failed: %w", err)
+	}
+	dest.File.KeepDays, _ = strconv.Atoi(keepDays)
+
+	return nil
+}
+
+func (p *Prompt) promptPostgresConfig(dest *Destination, defaultDest *Destination) error {
+	// Get default values
+	defaults := PostgresOptions{Schema: "public"}
+	if defaultDest != nil && defaultDest.Type == "postgres" {
+		defaults = defaultDest.Postgres
+	}
+
+	// Target database
+	databasePrompt := promptui.Prompt{
+		Label:     "Target Database (name in config.yaml)",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.Database,
+	}
+	database, err := databasePrompt.Run()
+	if err != nil {
+		return fmt.Errorf("database prompt failed: %w", err)
+	}
+	dest.Postgres.Database = database
+
+	// Target table
+	schemaPrompt := promptui.Prompt{
+		Label:     "Schema",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.Schema,
+	}
+	schema, err := schemaPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("schema prompt failed: %w", err)
+	}
+	dest.Postgres.Schema = schema
+
+	tablePrompt := promptui.Prompt{
+		Label:     "Table",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.Table,
+	}
+	table, err := tablePrompt.Run()
+	if err != nil {
+		return fmt.Errorf("table prompt failed: %w", err)
+	}
+	dest.Postgres.Table = table
+
+	// Write mode
+	modePrompt := promptui.Select{
+		Label: "Write Mode",
+		Items: []string{"append", "truncate", "upsert"},
+	}
+	_, mode, err := modePrompt.Run()
+	if err != nil {
+		return fmt.Errorf("write mode prompt failed: %w", err)
+	}
+	dest.Postgres.Mode = mode
+
+	if mode == "upsert" {
+		keysPrompt := promptui.Prompt{
+			Label:     "Key Columns (comma-separated)",
+			Validate:  validateNotEmpty,
+			AllowEdit: true,
+			Default:   strings.Join(defaults.KeyColumns, ","),
+		}
+		keys, err := keysPrompt.Run()
+		if err != nil {
+			return fmt.Errorf("key columns prompt failed: %w", err)
+		}
+		dest.Postgres.KeyColumns = nil
+		for _, key := range strings.Split(keys, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				dest.Postgres.KeyColumns = append(dest.Postgres.KeyColumns, key)
+			}
+		}
+	}
+
+	// Table creation
+	createPrompt := promptui.Select{
+		Label: "Create table if missing",
+		Items: []string{"yes", "no"},
+	}
+	idx, _, err := createPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("create table prompt failed: %w", err)
+	}
+	dest.Postgres.CreateTable = idx == 0
+
+	return nil
+}
+
+func (p *Prompt) promptKafkaConfig(dest *Destination, defaultDest *Destination) error {
+	// Get default values
+	var defaults KafkaOptions
+	if defaultDest != nil && defaultDest.Type == "kafka" {
+		defaults = defaultDest.Kafka
+	}
+
+	// Connection
+	brokersPrompt := promptui.Prompt{
+		Label:     "Brokers (comma-separated host:port)",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   strings.Join(defaults.Brokers, ","),
+	}
+	brokers, err := brokersPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("brokers prompt failed: %w", err)
+	}
+	for _, broker := range strings.Split(brokers, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			dest.Kafka.Brokers = append(dest.Kafka.Brokers, broker)
+		}
+	}
+
+	topicPrompt := promptui.Prompt{
+		Label:     "Topic",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.Topic,
+	}
+	topic, err := topicPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("topic prompt failed: %w", err)
+	}
+	dest.Kafka.Topic = topic
+
+	// Messages
+	modePrompt := promptui.Select{
+		Label: "Messages",
+		Items: []string{"one per row", "one per batch"},
+	}
+	idx, _, err := modePrompt.Run()
+	if err != nil {
+		return fmt.Errorf("mode prompt failed: %w", err)
+	}
+	if idx == 0 {
+		dest.Kafka.Mode = "row"
+		keyPrompt := promptui.Prompt{
+			Label:     "Key Column (optional)",
+			AllowEdit: true,
+			Default:   defaults.KeyColumn,
+		}
+		keyColumn, err := keyPrompt.Run()
+		if err != nil {
+			return fmt.Errorf("key column prompt failed: %w", err)
+		}
+		dest.Kafka.KeyColumn = strings.TrimSpace(keyColumn)
+	} else {
+		dest.Kafka.Mode = "batch"
+		dest.Kafka.BatchSize = defaults.BatchSize
+	}
+
+	acksPrompt := promptui.Select{
+		Label: "Acks",
+		Items: []string{"all", "one", "none"},
+	}
+	_, acks, err := acksPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("acks prompt failed: %w", err)
+	}
+	dest.Kafka.Acks = acks
+
+	// Security
+	saslPrompt := promptui.Select{
+		Label: "SASL",
+		Items: []string{"none", "plain", "scram-sha-256", "scram-sha-512"},
+	}
+	_, mechanism, err := saslPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("SASL prompt failed: %w", err)
+	}
+	if mechanism != "none" {
+		userPrompt := promptui.Prompt{
+			Label:     "SASL Username",
+			Validate:  validateNotEmpty,
+			AllowEdit: true,
+			Default:   defaults.SASL.Username,
+		}
+		username, err := userPrompt.Run()
+		if err != nil {
+			return fmt.Errorf("username prompt failed: %w", err)
+		}
+
+		passwordPrompt := promptui.Prompt{
+			Label:     "SASL Password",
+			Validate:  validateNotEmpty,
+			Mask:      '*',
+			AllowEdit: true,
+			Default:   defaults.SASL.Password,
+		}
+		password, err := passwordPrompt.Run()
+		if err != nil {
+			return fmt.Errorf("password prompt failed: %w", err)
+		}
+
+		dest.Kafka.SASL = KafkaSASL{
+			Mechanism: mechanism,
+			Username:  username,
+			Password:  password,
+		}
+	}
+
+	tlsPrompt := promptui.Select{
+		Label: "TLS",
+		Items: []string{"disabled", "enabled"},
+	}
+	idx, _, err = tlsPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("TLS prompt failed: %w", err)
+	}
+	if idx == 1 {
+		dest.Kafka.TLS = &TLSOptions{}
+		if defaults.TLS != nil {
+			dest.Kafka.TLS = defaults.TLS
+		}
+	}
+
+	return nil
+}
+
+func (p *Prompt) promptSpreadsheetConfig(dest *Destination, defaultDest *Destination) error {
+	// Get default values
+	var defaults SpreadsheetOptions
+	if defaultDest != nil && defaultDest.Type == "spreadsheet" {
+		defaults = defaultDest.Spreadsheet
+	}
+
+	providerPrompt := promptui.Select{
+		Label: "Provider",
+		Items: []string{"google"},
+	}
+	_, provider, err := providerPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("provider prompt failed: %w", err)
+	}
+	dest.Spreadsheet.Provider = provider
+
+	idPrompt := promptui.Prompt{
+		Label:     "Spreadsheet ID",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.SpreadsheetID,
+	}
+	spreadsheetID, err := idPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("spreadsheet ID prompt failed: %w", err)
+	}
+	dest.Spreadsheet.SpreadsheetID = strings.TrimSpace(spreadsheetID)
+
+	defaultSheet := defaults.Sheet
+	if defaultSheet == "" {
+		defaultSheet = "Sheet1"
+	}
+	sheetPrompt := promptui.Prompt{
+		Label:     "Sheet Name",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaultSheet,
+	}
+	sheet, err := sheetPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("sheet prompt failed: %w", err)
+	}
+	dest.Spreadsheet.Sheet = sheet
+
+	modePrompt := promptui.Select{
+		Label: "Write Mode",
+		Items: []string{"replace", "append"},
+	}
+	_, mode, err := modePrompt.Run()
+	if err != nil {
+		return fmt.Errorf("write mode prompt failed: %w", err)
+	}
+	dest.Spreadsheet.Mode = mode
+
+	headerPrompt := promptui.Select{
+		Label: "Write header row",
+		Items: []string{"yes", "no"},
+	}
+	idx, _, err := headerPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("header prompt failed: %w", err)
+	}
+	dest.Spreadsheet.SkipHeader = idx == 1
+
+	credentialsPrompt := promptui.Prompt{
+		Label:     "Service Account Key File",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.CredentialsFile,
+	}
+	credentialsFile, err := credentialsPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("credentials file prompt failed: %w", err)
+	}
+	dest.Spreadsheet.CredentialsFile = credentialsFile
+	dest.Spreadsheet.APIBaseURL = defaults.APIBaseURL
+
+	return nil
+}
+
 func (p *Prompt) promptCustomConfig(dest *Destination, defaultDest *Destination) error {
 	// Get default values
 	defaultURL := ""
@@ -162,10 +1034,21 @@ func (p *Prompt) promptCustomConfig(dest *Destination, defaultDest *Destination)
 	}
 	dest.URL = url
 
+	// HTTP method
+	methodPrompt := promptui.Select{
+		Label: "HTTP Method",
+		Items: []string{"POST", "PUT", "PATCH"},
+	}
+	_, method, err := methodPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("method prompt failed: %w", err)
+	}
+	dest.HTTP.Method = method
+
 	// Token type
 	tokenTypePrompt := promptui.Select{
 		Label: "Authentication Type",
-		Items: []string{"bearer", "basic", "api_key", "none"},
+		Items: []string{"bearer", "basic", "api_key", "hmac", "oauth2", "none"},
 	}
 	_, tokenType, err := tokenTypePrompt.Run()
 	if err != nil {
@@ -173,9 +1056,17 @@ func (p *Prompt) promptCustomConfig(dest *Destination, defaultDest *Destination)
 	}
 
 	if tokenType != "none" {
+		label := fmt.Sprintf("%s Token", tokenType)
+		switch tokenType {
+		case "hmac":
+			label = "HMAC Secret"
+		case "oauth2":
+			label = "Client Secret"
+		}
+
 		// Token value
 		tokenPrompt := promptui.Prompt{
-			Label:     fmt.Sprintf("%s Token", tokenType),
+			Label:     label,
 			Validate:  validateNotEmpty,
 			Mask:      '*',
 			AllowEdit: true,
@@ -189,8 +1080,180 @@ func (p *Prompt) promptCustomConfig(dest *Destination, defaultDest *Destination)
 			Type:  tokenType,
 			Value: tokenValue,
 		}
+
+		switch tokenType {
+		case "hmac":
+			if err := p.promptHMACConfig(dest, defaultDest); err != nil {
+				return err
+			}
+		case "oauth2":
+			if err := p.promptOAuth2Config(dest, defaultDest); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// promptNetworkConfig asks for the TLS and proxy settings of HTTP destinations.
+// Current values of dest.HTTP are offered as defaults.
+func (p *Prompt) promptNetworkConfig(dest *Destination) error {
+	fields := []struct {
+		label string
+		value *string
+	}{
+		{"CA Bundle File (optional)", &dest.HTTP.TLS.CAFile},
+		{"Client Certificate File (optional)", &dest.HTTP.TLS.CertFile},
+		{"Client Key File (optional)", &dest.HTTP.TLS.KeyFile},
+		{"Server Name Override (optional)", &dest.HTTP.TLS.ServerName},
+		{"Proxy URL (optional)", &dest.HTTP.Proxy},
+	}
+	for _, field := range fields {
+		prompt := promptui.Prompt{
+			Label:     field.label,
+			AllowEdit: true,
+			Default:   *field.value,
+		}
+		value, err := prompt.Run()
+		if err != nil {
+			return fmt.Errorf("network settings prompt failed: %w", err)
+		}
+		*field.value = strings.TrimSpace(value)
+	}
+
+	if (dest.HTTP.TLS.CertFile == "") != (dest.HTTP.TLS.KeyFile == "") {
+		return fmt.Errorf("client certificate and key must be set together")
+	}
+	if err := validateOptionalURL(dest.HTTP.Proxy); err != nil {
+		return fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	versionCursor := 0
+	if dest.HTTP.TLS.MinVersion == "1.3" {
+		versionCursor = 1
+	}
+	versionPrompt := promptui.Select{
+		Label:     "Minimum TLS Version",
+		Items:     []string{"1.2", "1.3"},
+		CursorPos: versionCursor,
+	}
+	_, version, err := versionPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("TLS version prompt failed: %w", err)
+	}
+	dest.HTTP.TLS.MinVersion = version
+
+	return nil
+}
+
+func (p *Prompt) promptHMACConfig(dest *Destination, defaultDest *Destination) error {
+	// Get default values
+	defaults := HMACConfig{
+		SignatureHeader: "X-Signature",
+		TimestampHeader: "X-Timestamp",
+	}
+	if defaultDest != nil && defaultDest.Token.Type == "hmac" {
+		defaults = defaultDest.Token.HMAC
+	}
+
+	algorithmPrompt := promptui.Select{
+		Label: "HMAC Algorithm",
+		Items: []string{"sha256", "sha512"},
+	}
+	_, algorithm, err := algorithmPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("algorithm prompt failed: %w", err)
+	}
+
+	signatureHeaderPrompt := promptui.Prompt{
+		Label:     "Signature Header",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.SignatureHeader,
+	}
+	signatureHeader, err := signatureHeaderPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("signature header prompt failed: %w", err)
+	}
+
+	timestampHeaderPrompt := promptui.Prompt{
+		Label:     "Timestamp Header",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.TimestampHeader,
+	}
+	timestampHeader, err := timestampHeaderPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("timestamp header prompt failed: %w", err)
 	}
 
+	dest.Token.HMAC = HMACConfig{
+		Algorithm:        algorithm,
+		SignatureHeader:  signatureHeader,
+		TimestampHeader:  timestampHeader,
+		SignaturePrefix:  defaults.SignaturePrefix,
+		ToleranceSeconds: defaults.ToleranceSeconds,
+	}
+	return nil
+}
+
+func (p *Prompt) promptOAuth2Config(dest *Destination, defaultDest *Destination) error {
+	// Get default values
+	var defaults OAuth2Config
+	if defaultDest != nil && defaultDest.Token.Type == "oauth2" {
+		defaults = defaultDest.Token.OAuth2
+	}
+
+	tokenURLPrompt := promptui.Prompt{
+		Label:     "Token URL",
+		Validate:  validateURL,
+		AllowEdit: true,
+		Default:   defaults.TokenURL,
+	}
+	tokenURL, err := tokenURLPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("token URL prompt failed: %w", err)
+	}
+
+	clientIDPrompt := promptui.Prompt{
+		Label:     "Client ID",
+		Validate:  validateNotEmpty,
+		AllowEdit: true,
+		Default:   defaults.ClientID,
+	}
+	clientID, err := clientIDPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("client ID prompt failed: %w", err)
+	}
+
+	scopesPrompt := promptui.Prompt{
+		Label:     "Scopes (space-separated, optional)",
+		AllowEdit: true,
+		Default:   strings.Join(defaults.Scopes, " "),
+	}
+	scopes, err := scopesPrompt.Run()
+	if err != nil {
+		return fmt.Errorf("scopes prompt failed: %w", err)
+	}
+
+	audiencePrompt := promptui.Prompt{
+		Label:     "Audience (optional)",
+		AllowEdit: true,
+		Default:   defaults.Audience,
+	}
+	audience, err := audiencePrompt.Run()
+	if err != nil {
+		return fmt.Errorf("audience prompt failed: %w", err)
+	}
+
+	dest.Token.OAuth2 = OAuth2Config{
+		TokenURL:  tokenURL,
+		ClientID:  clientID,
+		Scopes:    strings.Fields(scopes),
+		Audience:  strings.TrimSpace(audience),
+		AuthStyle: defaults.AuthStyle,
+	}
 	return nil
 }
 
@@ -202,6 +1265,28 @@ func validateNotEmpty(input string) error {
 	return nil
 }
 
+func validatePositiveInt(input string) error {
+	n, err := strconv.Atoi(input)
+	if err != nil {
+		return fmt.Errorf("value must be a number")
+	}
+	if n < 1 {
+		return fmt.Errorf("value must be greater than 0")
+	}
+	return nil
+}
+
+func validateNonNegativeInt(input string) error {
+	n, err := strconv.Atoi(input)
+	if err != nil {
+		return fmt.Errorf("value must be a number")
+	}
+	if n < 0 {
+		return fmt.Errorf("value cannot be negative")
+	}
+	return nil
+}
+
 func validateURL(input string) error {
 	if err := validateNotEmpty(input); err != nil {
 		return err
@@ -212,6 +1297,13 @@ func validateURL(input string) error {
 	return nil
 }
 
+func validateOptionalURL(input string) error {
+	if strings.TrimSpace(input) == "" {
+		return nil
+	}
+	return validateURL(input)
+}
+
 func validateSlackToken(input string) error {
 	if err := validateNotEmpty(input); err != nil {
 		return err
@@ -1,13 +1,182 @@
 package destination
 
 type Destination struct {
-	Type    string      `yaml:"type"` // slack, lineworks, custom
-	Token   TokenConfig `yaml:"token,omitempty"`
-	Channel string      `yaml:"channel,omitempty"`
-	URL     string      `yaml:"url,omitempty"`
+	Type        string             `yaml:"type"` // slack, lineworks, teams, discord, s3, sftp, file, postgres, kafka, spreadsheet, custom
+	Token       TokenConfig        `yaml:"token,omitempty"`
+	Channel     string             `yaml:"channel,omitempty"`
+	URL         string             `yaml:"url,omitempty"`
+	LinkURL     string             `yaml:"link_url,omitempty"` // link to the stored result, may contain {{task}}, {{date}}, {{timestamp}}
+	Slack       SlackOptions       `yaml:"slack,omitempty"`
+	Teams       TeamsOptions       `yaml:"teams,omitempty"`
+	S3          S3Options          `yaml:"s3,omitempty"`
+	SFTP        SFTPOptions        `yaml:"sftp,omitempty"`
+	File        FileOptions        `yaml:"file,omitempty"`
+	Postgres    PostgresOptions    `yaml:"postgres,omitempty"`
+	HTTP        HTTPOptions        `yaml:"http,omitempty"`
+	Kafka       KafkaOptions       `yaml:"kafka,omitempty"`
+	Spreadsheet SpreadsheetOptions `yaml:"spreadsheet,omitempty"`
+}
+
+// Redacted returns a copy of the destination with secrets masked for display
+func (d Destination) Redacted() Destination {
+	const mask = "********"
+	if d.Token.Value != "" {
+		d.Token.Value = mask
+	}
+	if d.S3.SecretAccessKey != "" {
+		d.S3.SecretAccessKey = mask
+	}
+	if d.SFTP.Password != "" {
+		d.SFTP.Password = mask
+	}
+	if d.SFTP.Passphrase != "" {
+		d.SFTP.Passphrase = mask
+	}
+	if d.Kafka.SASL.Password != "" {
+		d.Kafka.SASL.Password = mask
+	}
+	// Teams and Discord webhook URLs carry their credential
+	if (d.Type == "teams" || d.Type == "discord") && d.URL != "" {
+		d.URL = mask
+	}
+	return d
+}
+
+// SlackOptions holds Slack specific delivery settings
+type SlackOptions struct {
+	Delivery      string `yaml:"delivery,omitempty"`        // file (default), table
+	MaxTableRows  int    `yaml:"max_table_rows,omitempty"`  // above this a file is uploaded instead
+	MaxTableChars int    `yaml:"max_table_chars,omitempty"` // above this a file is uploaded instead
+}
+
+// TeamsOptions holds Microsoft Teams specific delivery settings
+type TeamsOptions struct {
+	MaxTableRows int `yaml:"max_table_rows,omitempty"` // rows shown in the card, default 10
+}
+
+// S3Options configures an S3 compatible object storage destination
+// (AWS S3, MinIO, GCS interoperability, ...)
+type S3Options struct {
+	Endpoint        string `yaml:"endpoint,omitempty"` // default https://s3.amazonaws.com
+	Region          string `yaml:"region,omitempty"`
+	Bucket          string `yaml:"bucket,omitempty"`
+	PathStyle       bool   `yaml:"path_style,omitempty"` // required by most MinIO setups
+	AccessKeyID     string `yaml:"access_key_id,omitempty"`
+	SecretAccessKey string `yaml:"secret_access_key,omitempty"`
+	KeyTemplate     string `yaml:"key_template,omitempty"` // default {{task}}/{{date}}/{{task}}_{{timestamp}}.{{ext}}
+	PartSizeMB      int    `yaml:"part_size_mb,omitempty"` // multipart upload part size, default 16
+}
+
+// SFTPOptions configures an SFTP file drop. Host keys are always verified
+// against known_hosts.
+type SFTPOptions struct {
+	Host             string `yaml:"host,omitempty"`
+	Port             int    `yaml:"port,omitempty"` // default 22
+	User             string `yaml:"user,omitempty"`
+	Password         string `yaml:"password,omitempty"`
+	PrivateKeyPath   string `yaml:"private_key_path,omitempty"`
+	Passphrase       string `yaml:"passphrase,omitempty"`       // for an encrypted private key
+	KnownHostsPath   string `yaml:"known_hosts_path,omitempty"` // default ~/.ssh/known_hosts
+	RemoteDir        string `yaml:"remote_dir,omitempty"`
+	FilenameTemplate string `yaml:"filename_template,omitempty"` // default {{task}}_{{timestamp}}.{{ext}}
+}
+
+// FileOptions configures a local directory destination
+type FileOptions struct {
+	Directory        string `yaml:"directory,omitempty"`
+	FilenameTemplate string `yaml:"filename_template,omitempty"` // default {{task}}_{{timestamp}}.{{ext}}
+	Partition        string `yaml:"partition,omitempty"`         // "" (none), daily (YYYY/MM/DD) or monthly (YYYY/MM)
+	KeepLast         int    `yaml:"keep_last,omitempty"`         // keep only the newest N files of the task
+	KeepDays         int    `yaml:"keep_days,omitempty"`         // remove files of the task older than N days
+}
+
+// PostgresOptions configures a table in one of the configured databases that
+// receives the query result
+type PostgresOptions struct {
+	Database    string   `yaml:"database,omitempty"` // name of a database in config.yaml
+	Schema      string   `yaml:"schema,omitempty"`   // default public
+	Table       string   `yaml:"table,omitempty"`
+	Mode        string   `yaml:"mode,omitempty"`         // append (default), truncate or upsert
+	KeyColumns  []string `yaml:"key_columns,omitempty"`  // conflict target for upsert
+	CreateTable bool     `yaml:"create_table,omitempty"` // create the table from the result columns if missing
+}
+
+// KafkaOptions configures publishing results to a Kafka topic
+type KafkaOptions struct {
+	Brokers   []string    `yaml:"brokers,omitempty"`
+	Topic     string      `yaml:"topic,omitempty"`
+	Mode      string      `yaml:"mode,omitempty"`       // row (one message per row, default) or batch
+	BatchSize int         `yaml:"batch_size,omitempty"` // rows per message in batch mode, default all
+	KeyColumn string      `yaml:"key_column,omitempty"` // message key in row mode, default none
+	Acks      string      `yaml:"acks,omitempty"`       // all (default), one or none
+	SASL      KafkaSASL   `yaml:"sasl,omitempty"`
+	TLS       *TLSOptions `yaml:"tls,omitempty"` // set to connect with TLS, tls: {} uses the system roots
+}
+
+// KafkaSASL holds SASL credentials for Kafka
+type KafkaSASL struct {
+	Mechanism string `yaml:"mechanism,omitempty"` // plain, scram-sha-256 or scram-sha-512
+	Username  string `yaml:"username,omitempty"`
+	Password  string `yaml:"password,omitempty"`
+}
+
+// SpreadsheetOptions configures writing results into a spreadsheet
+type SpreadsheetOptions struct {
+	Provider        string `yaml:"provider,omitempty"`       // google (default)
+	SpreadsheetID   string `yaml:"spreadsheet_id,omitempty"` // from the sheet URL
+	Sheet           string `yaml:"sheet,omitempty"`          // tab name, default Sheet1
+	Mode            string `yaml:"mode,omitempty"`           // replace (clear and write, default) or append
+	SkipHeader      bool   `yaml:"skip_header,omitempty"`
+	CredentialsFile string `yaml:"credentials_file,omitempty"` // service account JSON key
+	APIBaseURL      string `yaml:"api_base_url,omitempty"`     // default https://sheets.googleapis.com
+}
+
+// HTTPOptions shapes the request sent by custom destinations. TLS and Proxy
+// apply to every HTTP based destination.
+type HTTPOptions struct {
+	Method         string            `yaml:"method,omitempty"`        // default POST
+	Headers        map[string]string `yaml:"headers,omitempty"`       // values may contain {{task}}, {{date}}, {{timestamp}}
+	Query          map[string]string `yaml:"query,omitempty"`         // values may contain {{task}}, {{date}}, {{timestamp}}
+	BodyTemplate   string            `yaml:"body_template,omitempty"` // Go template, default is the raw result file
+	ContentType    string            `yaml:"content_type,omitempty"`
+	ExpectedStatus []int             `yaml:"expected_status,omitempty"` // default any 2xx
+	ReceiptPath    string            `yaml:"receipt_path,omitempty"`    // JSON path of a receipt ID in the response, e.g. $.data.id
+	TLS            TLSOptions        `yaml:"tls,omitempty"`
+	Proxy          string            `yaml:"proxy,omitempty"` // e.g. http://proxy.internal:3128, default from HTTPS_PROXY
+}
+
+// TLSOptions configures server verification and client certificates
+type TLSOptions struct {
+	CAFile     string `yaml:"ca_file,omitempty"`     // PEM bundle trusted in addition to the system roots
+	CertFile   string `yaml:"cert_file,omitempty"`   // client certificate for mutual TLS
+	KeyFile    string `yaml:"key_file,omitempty"`    // client key for mutual TLS
+	ServerName string `yaml:"server_name,omitempty"` // overrides the name verified in the server certificate
+	MinVersion string `yaml:"min_version,omitempty"` // 1.2 (default) or 1.3
 }
 
 type TokenConfig struct {
-	Type  string `yaml:"type,omitempty"` // bearer, basic, api_key
-	Value string `yaml:"value,omitempty"`
+	Type   string       `yaml:"type,omitempty"` // bearer, basic, api_key, hmac, oauth2; bot or webhook for slack
+	Value  string       `yaml:"value,omitempty"`
+	HMAC   HMACConfig   `yaml:"hmac,omitempty"`
+	OAuth2 OAuth2Config `yaml:"oauth2,omitempty"`
+}
+
+// HMACConfig configures request signing for the hmac token type. The token
+// value is the shared secret and the signature covers "<timestamp>.<body>".
+type HMACConfig struct {
+	Algorithm        string `yaml:"algorithm,omitempty"`         // sha256 (default) or sha512
+	SignatureHeader  string `yaml:"signature_header,omitempty"`  // default X-Signature
+	TimestampHeader  string `yaml:"timestamp_header,omitempty"`  // default X-Timestamp
+	SignaturePrefix  string `yaml:"signature_prefix,omitempty"`  // e.g. "sha256="
+	ToleranceSeconds int    `yaml:"tolerance_seconds,omitempty"` // accepted clock skew of the receiver, default 300
+}
+
+// OAuth2Config configures the OAuth2 client credentials grant for the oauth2
+// token type. The token value is the client secret.
+type OAuth2Config struct {
+	TokenURL  string   `yaml:"token_url,omitempty"`
+	ClientID  string   `yaml:"client_id,omitempty"`
+	Scopes    []string `yaml:"scopes,omitempty"`
+	Audience  string   `yaml:"audience,omitempty"`
+	AuthStyle string   `yaml:"auth_style,omitempty"` // header (HTTP basic, default) or body
 }
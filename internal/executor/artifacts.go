@@ -0,0 +1,161 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ONCALLJP/goractor/internal/task"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	bundleTaskFile   = "task.yaml"
+	bundleResultFile = "result.json"
+)
+
+// writeResultBundle stores everything needed to deliver a result again without
+// querying the database: the task snapshot, the query result and the rendered file
+func writeResultBundle(dir string, t *task.Task, result QueryResult, resultFilePath string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	taskData, err := yaml.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("failed to encode task: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, bundleTaskFile), taskData, 0600); err != nil {
+		return fmt.Errorf("failed to write task snapshot: %w", err)
+	}
+
+	resultData, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, bundleResultFile), resultData, 0600); err != nil {
+		return fmt.Errorf("failed to write result: %w", err)
+	}
+
+	return copyFile(resultFilePath, filepath.Join(dir, filepath.Base(resultFilePath)))
+}
+
+// readResultBundle loads a bundle written by writeResultBundle
+func readResultBundle(dir string) (task.Task, QueryResult, error) {
+	taskData, err := os.ReadFile(filepath.Join(dir, bundleTaskFile))
+	if err != nil {
+		return task.Task{}, QueryResult{}, fmt.Errorf("failed to read task snapshot: %w", err)
+	}
+	var t task.Task
+	if err := yaml.Unmarshal(taskData, &t); err != nil {
+		return task.Task{}, QueryResult{}, fmt.Errorf("failed to parse task snapshot: %w", err)
+	}
+
+	resultData, err := os.ReadFile(filepath.Join(dir, bundleResultFile))
+	if err != nil {
+		return task.Task{}, QueryResult{}, fmt.Errorf("failed to read result: %w", err)
+	}
+	result, err := decodeResult(resultData)
+	if err != nil {
+		return task.Task{}, QueryResult{}, err
+	}
+	return t, result, nil
+}
+
+// SetArtifactRetention sets how many rendered results are kept per task for
+// resending. Zero disables keeping them.
+func (e *Executor) SetArtifactRetention(keepLast int) {
+	e.keepArtifacts = keepLast
+}
+
+func (e *Executor) artifactDir(taskName, runID string) string {
+	return filepath.Join(e.runsDir(taskName), runID)
+}
+
+// saveArtifact keeps the rendered result of a run if retention is enabled
+func (e *Executor) saveArtifact(t *task.Task, result QueryResult, resultFilePath string, run *RunRecord) error {
+	if e.keepArtifacts <= 0 || e.stateDir == "" {
+		return nil
+	}
+
+	if err := writeResultBundle(e.artifactDir(t.Name, run.ID), t, result, resultFilePath); err != nil {
+		return err
+	}
+	run.Artifact = filepath.Base(resultFilePath)
+	return e.pruneArtifacts(t.Name)
+}
+
+// pruneArtifacts removes the oldest stored results beyond the retention
+func (e *Executor) pruneArtifacts(taskName string) error {
+	entries, err := os.ReadDir(e.runsDir(taskName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read runs directory: %w", err)
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			ids = append(ids, entry.Name())
+		}
+	}
+	sort.Strings(ids)
+
+	for len(ids) > e.keepArtifacts {
+		if err := os.RemoveAll(e.artifactDir(taskName, ids[0])); err != nil {
+			return fmt.Errorf("failed to remove old result: %w", err)
+		}
+		ids = ids[1:]
+	}
+	return nil
+}
+
+// Resend delivers the stored result of a past run again without querying the
+// database. An empty destinationName uses the destination of the original run.
+// Slack mention rules only apply when mentions is set, so resending an old
+// report does not page anyone again by default.
+func (e *Executor) Resend(ctx context.Context, taskName, runID, destinationName string, mentions bool) (err error) {
+	original, err := e.GetRun(taskName, runID)
+	if err != nil {
+		return err
+	}
+
+	dir := e.artifactDir(taskName, filepath.Base(runID))
+	if original.Artifact == "" {
+		return fmt.Errorf("no stored result for run %s (artifact retention disabled or result pruned)", runID)
+	}
+	if _, err := os.Stat(filepath.Join(dir, original.Artifact)); err != nil {
+		return fmt.Errorf("no stored result for run %s (artifact retention disabled or result pruned)", runID)
+	}
+
+	t, result, err := readResultBundle(dir)
+	if err != nil {
+		return err
+	}
+	if destinationName != "" {
+		t.DestinationName = destinationName
+	}
+	// Slack thread state belongs to the task's regular runs; a resend must not
+	// edit or replace it, so it is posted as a new message
+	t.Slack.Threading = ""
+	if !mentions {
+		t.Slack.Mentions = nil
+	}
+
+	dest, exists := e.destinationManager.Get(t.DestinationName)
+	if !exists {
+		return fmt.Errorf("destination %s not found", t.DestinationName)
+	}
+
+	run := newRunRecord(&t)
+	run.RowCount = result.RowCount
+	run.ResentFrom = original.ID
+	defer func() { e.finishRun(run, err) }()
+
+	return e.deliver(ctx, &t, dest, result, filepath.Join(dir, original.Artifact), run)
+}
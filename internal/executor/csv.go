@@ -0,0 +1,130 @@
+package executor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ONCALLJP/goractor/internal/task"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// csvWriter writes CSV records honouring the task's CSVOptions.
+// Unlike encoding/csv it supports quoting every field.
+type csvWriter struct {
+	w         *bufio.Writer
+	encoder   io.Closer
+	delimiter rune
+	quoteAll  bool
+	newline   string
+}
+
+func newCSVWriter(w io.Writer, opts task.CSVOptions) (*csvWriter, error) {
+	delimiter, err := csvDelimiter(opts.Delimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	var encoder io.WriteCloser
+	newline := "\n"
+	switch strings.ToLower(opts.LineEnding) {
+	case "", "lf":
+	case "crlf":
+		newline = "\r\n"
+	default:
+		return nil, fmt.Errorf("unsupported line ending: %s", opts.LineEnding)
+	}
+
+	switch strings.ToLower(opts.Encoding) {
+	case "", "utf-8", "utf8":
+		if opts.BOM {
+			if _, err := w.Write(utf8BOM); err != nil {
+				return nil, fmt.Errorf("failed to write BOM: %w", err)
+			}
+		}
+	case "shift_jis", "sjis", "cp932", "windows-31j":
+		// Characters outside Shift_JIS are replaced rather than failing the whole report
+		encoder = transform.NewWriter(w, encoding.ReplaceUnsupported(japanese.ShiftJIS.NewEncoder()))
+		w = encoder
+	default:
+		return nil, fmt.Errorf("unsupported CSV encoding: %s", opts.Encoding)
+	}
+
+	return &csvWriter{
+		w:         bufio.NewWriter(w),
+		encoder:   encoder,
+		delimiter: delimiter,
+		quoteAll:  opts.QuoteAll,
+		newline:   newline,
+	}, nil
+}
+
+func csvDelimiter(input string) (rune, error) {
+	switch input {
+	case "":
+		return ',', nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(input)
+	if size != len(input) || r == '"' || r == '\r' || r == '\n' {
+		return 0, fmt.Errorf("invalid CSV delimiter: %q", input)
+	}
+	return r, nil
+}
+
+func (c *csvWriter) Write(record []string) error {
+	for i, field := range record {
+		if i > 0 {
+			if _, err := c.w.WriteRune(c.delimiter); err != nil {
+				return err
+			}
+		}
+
+		if !c.quoteAll && !c.fieldNeedsQuotes(field) {
+			if _, err := c.w.WriteString(field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := c.w.WriteByte('"'); err != nil {
+			return err
+		}
+		if _, err := c.w.WriteString(strings.ReplaceAll(field, `"`, `""`)); err != nil {
+			return err
+		}
+		if err := c.w.WriteByte('"'); err != nil {
+			return err
+		}
+	}
+	_, err := c.w.WriteString(c.newline)
+	return err
+}
+
+// Close flushes buffered output. It does not close the underlying writer.
+func (c *csvWriter) Close() error {
+	if err := c.w.Flush(); err != nil {
+		return err
+	}
+	if c.encoder != nil {
+		return c.encoder.Close()
+	}
+	return nil
+}
+
+func (c *csvWriter) fieldNeedsQuotes(field string) bool {
+	if field == "" {
+		return false
+	}
+	if field[0] == ' ' || field[0] == '\t' {
+		return true
+	}
+	return strings.ContainsRune(field, c.delimiter) || strings.ContainsAny(field, "\"\r\n")
+}
@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+func TestCSVWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    task.CSVOptions
+		records [][]string
+		want    string
+	}{
+		{
+			name:    "default",
+			records: [][]string{{"id", "name"}, {"1", "Alice"}},
+			want:    "id,name\n1,Alice\n",
+		},
+		{
+			name:    "quotes only when needed",
+			records: [][]string{{"a,b", `say "hi"`, "line\nbreak", " lead", "plain", ""}},
+			want:    "\"a,b\",\"say \"\"hi\"\"\",\"line\nbreak\",\" lead\",plain,\n",
+		},
+		{
+			name:    "quote all",
+			opts:    task.CSVOptions{QuoteAll: true},
+			records: [][]string{{"1", ""}},
+			want:    "\"1\",\"\"\n",
+		},
+		{
+			name:    "tab delimiter",
+			opts:    task.CSVOptions{Delimiter: "tab"},
+			records: [][]string{{"a,b", "c\td"}},
+			want:    "a,b\t\"c\td\"\n",
+		},
+		{
+			name:    "semicolon delimiter",
+			opts:    task.CSVOptions{Delimiter: ";"},
+			records: [][]string{{"a;b", "c,d"}},
+			want:    "\"a;b\";c,d\n",
+		},
+		{
+			name:    "crlf",
+			opts:    task.CSVOptions{LineEnding: "crlf"},
+			records: [][]string{{"a"}, {"b"}},
+			want:    "a\r\nb\r\n",
+		},
+		{
+			name:    "utf-8 bom",
+			opts:    task.CSVOptions{BOM: true},
+			records: [][]string{{"a"}},
+			want:    "\xEF\xBB\xBFa\n",
+		},
+		{
+			name:    "shift_jis",
+			opts:    task.CSVOptions{Encoding: "shift_jis"},
+			records: [][]string{{"日本"}},
+			want:    "\x93\xfa\x96\x7b\n",
+		},
+		{
+			name:    "shift_jis replaces unsupported characters",
+			opts:    task.CSVOptions{Encoding: "cp932"},
+			records: [][]string{{"a😀b"}},
+			want:    "a\x1ab\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := newCSVWriter(&buf, tt.opts)
+			if err != nil {
+				t.Fatalf("newCSVWriter error: %v", err)
+			}
+			for _, record := range tt.records {
+				if err := w.Write(record); err != nil {
+					t.Fatalf("Write error: %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close error: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVWriterInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts task.CSVOptions
+	}{
+		{name: "multi-character delimiter", opts: task.CSVOptions{Delimiter: ";;"}},
+		{name: "quote delimiter", opts: task.CSVOptions{Delimiter: `"`}},
+		{name: "newline delimiter", opts: task.CSVOptions{Delimiter: "\n"}},
+		{name: "line ending", opts: task.CSVOptions{LineEnding: "cr"}},
+		{name: "encoding", opts: task.CSVOptions{Encoding: "latin1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newCSVWriter(&bytes.Buffer{}, tt.opts); err == nil {
+				t.Errorf("newCSVWriter(%+v) succeeded, want error", tt.opts)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+// webhookData is the data available to custom destination body templates
+type webhookData struct {
+	Task          string
+	Message       string
+	Database      string
+	Format        string
+	RunTime       string // RFC 3339 in the task's timezone
+	Date          string
+	Timestamp     string
+	ExecutionTime string
+	RowCount      int
+	Columns       []Column
+	Rows          []map[string]interface{} // values rendered as in JSON output
+	Content       string                   // the rendered result file
+	ContentBase64 string
+}
+
+var webhookFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+func (e *Executor) sendToCustom(ctx context.Context, t *task.Task, dest destination.Destination, result QueryResult, resultFilePath string, run *RunRecord) error {
+	opts := dest.HTTP
+
+	// Read file content
+	content, err := os.ReadFile(resultFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read result file: %w", err)
+	}
+
+	body := content
+	reqContentType := contentType(t.OutputFormat)
+	if opts.BodyTemplate != "" {
+		body, err = renderWebhookBody(opts.BodyTemplate, t, result, content)
+		if err != nil {
+			return err
+		}
+		reqContentType = "application/json"
+	}
+	if opts.ContentType != "" {
+		reqContentType = opts.ContentType
+	}
+
+	requestURL, err := url.Parse(dest.URL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if len(opts.Query) > 0 {
+		query := requestURL.Query()
+		for key, value := range opts.Query {
+			query.Set(key, expandTemplate(value, t, result.Timestamp))
+		}
+		requestURL.RawQuery = query.Encode()
+	}
+
+	method := opts.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	client, err := e.httpClient(dest)
+	if err != nil {
+		return err
+	}
+
+	// Send request, retrying once with a fresh token if an oauth2 token was rejected
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, requestURL.String(), bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Content-Type", reqContentType)
+		for key, value := range opts.Headers {
+			req.Header.Set(key, expandTemplate(value, t, result.Timestamp))
+		}
+
+		// Set authentication based on token type
+		signedAt := time.Now()
+		if err := e.authorizeRequest(ctx, req, dest, body, signedAt); err != nil {
+			return err
+		}
+
+		if dest.Token.Type == "hmac" {
+			// The receiver rejects signatures older than its tolerance, so a
+			// request still running by then cannot succeed
+			signedCtx, cancel := context.WithDeadline(ctx, signedAt.Add(hmacTolerance(dest.Token.HMAC)))
+			defer cancel()
+			req = req.WithContext(signedCtx)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			if dest.Token.Type == "hmac" && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+				return fmt.Errorf("request not completed within the %s signature tolerance: %w", hmacTolerance(dest.Token.HMAC), err)
+			}
+			return fmt.Errorf("failed to send request: %w", err)
+		}
+		respBody, readErr := io.ReadAll(io.LimitReader(resp.Body, maxReceiptBody))
+		resp.Body.Close()
+		if readErr != nil && !expectedStatus(resp.StatusCode, opts.ExpectedStatus) {
+			return fmt.Errorf("failed to read response: %w", readErr)
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && dest.Token.Type == "oauth2" && attempt == 1 {
+			e.oauth2Tokens.invalidate(dest.Token)
+			continue
+		}
+
+		run.Response = captureResponse(resp, respBody)
+
+		if !expectedStatus(resp.StatusCode, opts.ExpectedStatus) {
+			detail := responseSnippet(respBody)
+			if dest.Token.Type == "hmac" {
+				if hint := hmacSkewHint(resp, dest.Token.HMAC, signedAt); hint != "" {
+					detail = strings.TrimPrefix(detail+" ("+hint+")", " ")
+				}
+			}
+			err := fmt.Errorf("received unexpected status code: %d", resp.StatusCode)
+			if detail != "" {
+				err = fmt.Errorf("received unexpected status code: %d: %s", resp.StatusCode, detail)
+			}
+			if retryableStatus(resp.StatusCode) {
+				return retryable(err)
+			}
+			return err
+		}
+
+		// The request was accepted, so problems with the response must not
+		// fail the delivery and cause it to be sent again
+		if readErr != nil {
+			run.warn("failed to read response: %v", readErr)
+		}
+		if opts.ReceiptPath != "" {
+			receipt, err := extractJSONPath(respBody, opts.ReceiptPath)
+			if err != nil {
+				run.warn("failed to extract receipt: %v", err)
+			} else {
+				run.ReceiptID = receipt
+			}
+		}
+		return nil
+	}
+}
+
+func renderWebhookBody(bodyTemplate string, t *task.Task, result QueryResult, content []byte) ([]byte, error) {
+	tmpl, err := template.New("body").Funcs(webhookFuncs).Option("missingkey=zero").Parse(bodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body template: %w", err)
+	}
+
+	headers := resultHeaders(result, t.Columns)
+	formatter := newValueFormatter(t)
+	rows := make([]map[string]interface{}, 0, len(result.Data))
+	for _, row := range result.Data {
+		values := make(map[string]interface{}, len(headers))
+		for _, header := range headers {
+			values[header] = formatter.JSON(row[header], result.ColumnType(header))
+		}
+		rows = append(rows, values)
+	}
+
+	format := t.OutputFormat
+	if format == "" {
+		format = "csv"
+	}
+	runTime := result.Timestamp.In(formatter.loc)
+
+	data := webhookData{
+		Task:          t.Name,
+		Message:       t.Message,
+		Database:      t.Database,
+		Format:        format,
+		RunTime:       runTime.Format(time.RFC3339),
+		Date:          runTime.Format("2006-01-02"),
+		Timestamp:     runTime.Format("20060102_150405"),
+		ExecutionTime: result.ExecutionTime,
+		RowCount:      result.RowCount,
+		Columns:       result.Columns,
+		Rows:          rows,
+		Content:       string(content),
+		ContentBase64: base64.StdEncoding.EncodeToString(content),
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render body template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// expectedStatus reports whether code is acceptable; without a list any 2xx is
+func expectedStatus(code int, expected []int) bool {
+	if len(expected) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, c := range expected {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+// Discord limits message content to 2000 characters
+const discordMaxContentChars = 2000
+
+func (e *Executor) sendToDiscord(ctx context.Context, t *task.Task, dest destination.Destination, resultFilePath string) error {
+	content := t.Message
+	if utf8.RuneCountInString(content) > discordMaxContentChars {
+		content = string([]rune(content)[:discordMaxContentChars-1]) + "…"
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"content": content,
+		// Never ping @everyone or roles from query output
+		"allowed_mentions": map[string]interface{}{"parse": []string{}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode discord payload: %w", err)
+	}
+
+	resultFile, err := os.Open(resultFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to open result file: %w", err)
+	}
+	defer resultFile.Close()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("payload_json", string(payload)); err != nil {
+		return fmt.Errorf("failed to write discord payload: %w", err)
+	}
+	part, err := writer.CreateFormFile("files[0]", filepath.Base(resultFilePath))
+	if err != nil {
+		return fmt.Errorf("failed to create discord attachment: %w", err)
+	}
+	if _, err := io.Copy(part, resultFile); err != nil {
+		return fmt.Errorf("failed to write discord attachment: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to finish discord request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", dest.URL, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	client, err := e.httpClient(dest)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("received non-success status code from discord: %d %s", resp.StatusCode, bytes.TrimSpace(respBody))
+		if retryableStatus(resp.StatusCode) {
+			return retryable(err)
+		}
+		return err
+	}
+	return nil
+}
@@ -1,27 +1,29 @@
 package executor
 
 import (
-	"bytes"
 	"context"
 	"database/sql"
-	"encoding/csv"
 	"fmt"
-	"net/http"
 	"os"
 	"path/filepath"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/ONCALLJP/goractor/internal/config"
 	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/secret"
 	"github.com/ONCALLJP/goractor/internal/task"
 	_ "github.com/lib/pq"
-	"github.com/slack-go/slack"
 )
 
 type Executor struct {
 	dbConfigs          map[string]*config.DBConfig
 	destinationManager *destination.Manager
+	stateDir           string // directory for state kept between runs, e.g. ~/.goractor
+	oauth2Tokens       *oauth2Cache
+	outboxTTL          time.Duration
+	outboxMu           sync.Mutex
+	keepArtifacts      int // rendered results kept per task for resending
 }
 
 type DBConfig struct {
@@ -38,30 +40,46 @@ type QueryResult struct {
 	QueryName     string                   `json:"query_name"`
 	ExecutionTime string                   `json:"execution_time"`
 	RowCount      int                      `json:"row_count"`
+	Columns       []Column                 `json:"columns"`
 	Data          []map[string]interface{} `json:"data"`
 }
 
-func NewExecutor(dbConfigs map[string]*config.DBConfig, dest *destination.Manager) *Executor {
+// Column describes a result column in query order
+type Column struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // PostgreSQL type name, e.g. INT4, NUMERIC, TIMESTAMPTZ
+}
+
+// ColumnType returns the database type of the named column, or "" if unknown
+func (r QueryResult) ColumnType(name string) string {
+	for _, col := range r.Columns {
+		if col.Name == name {
+			return col.Type
+		}
+	}
+	return ""
+}
+
+func NewExecutor(dbConfigs map[string]*config.DBConfig, dest *destination.Manager, stateDir string) *Executor {
 	return &Executor{
 		dbConfigs:          dbConfigs,
 		destinationManager: dest,
+		stateDir:           stateDir,
+		oauth2Tokens:       newOAuth2Cache(),
 	}
 }
 
-func (e *Executor) Execute(ctx context.Context, t *task.Task) error {
-	// Get database configuration
-	dbConfig, ok := e.dbConfigs[t.Database]
-	if !ok {
-		return fmt.Errorf("database configuration not found: %s", t.Database)
-	}
+func (e *Executor) Execute(ctx context.Context, t *task.Task) (err error) {
+	// Deferred first so queued results are retried after this run's delivery
+	defer e.retryPending(t)
 
-	// Connect to database
-	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		dbConfig.Host, dbConfig.Port, dbConfig.User, dbConfig.Password, dbConfig.DBName)
+	run := newRunRecord(t)
+	defer func() { e.finishRun(run, err) }()
 
-	db, err := sql.Open("postgres", connStr)
+	// Connect to database
+	db, err := e.openDatabase(t.Database)
 	if err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+		return err
 	}
 	defer db.Close()
 
@@ -79,6 +97,11 @@ func (e *Executor) Execute(ctx context.Context, t *task.Task) error {
 		return fmt.Errorf("failed to get columns: %w", err)
 	}
 
+	resultColumns, err := columnsOf(rows)
+	if err != nil {
+		return err
+	}
+
 	// Prepare result
 	var result []map[string]interface{}
 	count := 0
@@ -99,12 +122,7 @@ func (e *Executor) Execute(ctx context.Context, t *task.Task) error {
 		// Create a map for this row
 		row := make(map[string]interface{})
 		for i, col := range columns {
-			val := values[i]
-			if b, ok := val.([]byte); ok {
-				row[col] = string(b)
-			} else {
-				row[col] = val
-			}
+			row[col] = convertValue(values[i], resultColumns[i].Type)
 		}
 
 		result = append(result, row)
@@ -117,28 +135,76 @@ func (e *Executor) Execute(ctx context.Context, t *task.Task) error {
 		Timestamp:     time.Now(),
 		ExecutionTime: time.Since(start).String(),
 		RowCount:      count,
+		Columns:       resultColumns,
 		Data:          result,
 	}
 
-	if t.OutputFormat == "csv" {
-		if err := e.sendResultAsCSV(ctx, t, queryResult); err != nil {
-			return fmt.Errorf("failed to send to destination: %w", err)
-		}
-		return e.sendResultAsCSV(ctx, t, queryResult)
-	} else if t.OutputFormat == "json" {
-		fmt.Println("✓ Destination test successful")
-		return nil
+	if err := e.sendResult(ctx, t, queryResult, run); err != nil {
+		return fmt.Errorf("failed to send to destination: %w", err)
 	}
 	return nil
 }
 
-func (e *Executor) createCSVFile(result QueryResult, headers []string) (string, error) {
-	// If we couldn't parse SQL, fallback to the order from result
-	if len(headers) == 0 && len(result.Data) > 0 {
-		for col := range result.Data[0] {
-			headers = append(headers, col)
-		}
+// openDatabase opens a connection to a configured database
+func (e *Executor) openDatabase(name string) (*sql.DB, error) {
+	dbConfig, ok := e.dbConfigs[name]
+	if !ok {
+		return nil, fmt.Errorf("database configuration not found: %s", name)
+	}
+	if err := secret.Check(dbConfig); err != nil {
+		return nil, fmt.Errorf("database %s: %w", name, err)
+	}
+
+	db, err := sql.Open("postgres", dbConfig.ConnectionString())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
+	return db, nil
+}
+
+// columnsOf returns the result columns with their database types
+func columnsOf(rows *sql.Rows) ([]Column, error) {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get column types: %w", err)
+	}
+
+	columns := make([]Column, 0, len(columnTypes))
+	for _, ct := range columnTypes {
+		columns = append(columns, Column{
+			Name: ct.Name(),
+			Type: ct.DatabaseTypeName(),
+		})
+	}
+	return columns, nil
+}
+
+// resultHeaders returns the configured columns, falling back to the query order
+func resultHeaders(result QueryResult, headers []string) []string {
+	if len(headers) > 0 {
+		return headers
+	}
+	for _, col := range result.Columns {
+		headers = append(headers, col.Name)
+	}
+	return headers
+}
+
+func (e *Executor) createResultFile(t *task.Task, result QueryResult) (string, error) {
+	switch t.OutputFormat {
+	case "xlsx":
+		return e.createXLSXFile(t, result)
+	case "json":
+		return e.createJSONFile(t, result)
+	default:
+		return e.createCSVFile(t, result)
+	}
+}
+
+func (e *Executor) createCSVFile(t *task.Task, result QueryResult) (string, error) {
+	headers := resultHeaders(result, t.Columns)
+	formatter := newValueFormatter(t)
+	opts := t.CSV
 
 	// Create CSV file
 	tmpDir := filepath.Join(os.TempDir(), "goractor")
@@ -154,21 +220,25 @@ func (e *Executor) createCSVFile(result QueryResult, headers []string) (string,
 	}
 	defer file.Close()
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	writer, err := newCSVWriter(file, opts)
+	if err != nil {
+		return "", err
+	}
 
 	// Write headers
-	if err := writer.Write(headers); err != nil {
-		return "", fmt.Errorf("failed to write CSV headers: %w", err)
+	if !opts.SkipHeader {
+		if err := writer.Write(headers); err != nil {
+			return "", fmt.Errorf("failed to write CSV headers: %w", err)
+		}
 	}
 
 	// Write data in the same order as headers
 	for _, row := range result.Data {
 		var record []string
 		for _, header := range headers {
-			value := ""
+			value := opts.NullValue
 			if v := row[header]; v != nil {
-				value = fmt.Sprintf("%v", v)
+				value = formatter.Text(v, result.ColumnType(header))
 			}
 			record = append(record, value)
 		}
@@ -177,123 +247,125 @@ func (e *Executor) createCSVFile(result QueryResult, headers []string) (string,
 		}
 	}
 
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to write CSV file: %w", err)
+	}
+
 	return filename, nil
 }
 
-func (e *Executor) sendResultAsCSV(ctx context.Context, t *task.Task, result QueryResult) error {
+func (e *Executor) sendResult(ctx context.Context, t *task.Task, result QueryResult, run *RunRecord) error {
 	// Get destination configuration
 	dest, exists := e.destinationManager.Get(t.DestinationName)
 	if !exists {
 		return fmt.Errorf("destination %s not found", t.DestinationName)
 	}
 
+	run.RowCount = result.RowCount
 	if len(result.Data) == 0 {
 		return fmt.Errorf("no data to send")
 	}
 
-	// Create CSV file
-	csvFilePath, err := e.createCSVFile(result, t.Columns)
+	// Create result file
+	resultFilePath, err := e.createResultFile(t, result)
 	if err != nil {
-		return fmt.Errorf("failed to create CSV file: %w", err)
+		return fmt.Errorf("failed to create result file: %w", err)
 	}
-	defer os.Remove(csvFilePath)
+	defer os.Remove(resultFilePath)
 
-	// Open the file for reading
-	csvFile, err := os.Open(csvFilePath)
-	if err != nil {
-		return fmt.Errorf("failed to open CSV file: %w", err)
+	if err := e.saveArtifact(t, result, resultFilePath, run); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to keep result for resending: %v\n", err)
 	}
-	defer csvFile.Close()
 
-	fileStat, err := csvFile.Stat()
-	if err != nil {
-		panic(err)
+	if err := e.deliver(ctx, t, dest, result, resultFilePath, run); err != nil {
+		if !isRetryable(err) {
+			return err
+		}
+
+		// Keep the result so it can be delivered once the destination is back
+		if id, qerr := e.enqueue(t, result, resultFilePath, run, err); qerr != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to queue result in outbox: %v\n", qerr)
+		} else {
+			run.Outbox = id
+			fmt.Printf("Result queued in outbox as %s\n", id)
+		}
+		return err
+	}
+	return nil
+}
+
+// deliver sends a rendered result to a destination
+func (e *Executor) deliver(ctx context.Context, t *task.Task, dest destination.Destination, result QueryResult, resultFilePath string, run *RunRecord) error {
+	if err := secret.Check(dest); err != nil {
+		return fmt.Errorf("destination %s: %w", t.DestinationName, err)
 	}
-	size := fileStat.Size()
 
 	switch dest.Type {
 	case "slack":
-		api := slack.New(dest.Token.Value)
-		params := slack.UploadFileV2Parameters{
-			Filename:       filepath.Base(csvFilePath),
-			FileSize:       int(size),
-			Channel:        strings.Replace(dest.Channel, "#", "", 1),
-			File:           csvFilePath,
-			Reader:         csvFile,
-			InitialComment: t.Message,
-		}
-		_, err = api.UploadFileV2(params)
-		if err != nil {
-			return fmt.Errorf("failed to upload file to slack: %w", err)
-		}
+		return e.sendToSlack(ctx, t, dest, result, resultFilePath)
 
 	case "lineworks":
 		return fmt.Errorf("lineworks implementation pending")
 
-	case "custom":
-		// Read file content
-		content, err := os.ReadFile(csvFilePath)
-		if err != nil {
-			return fmt.Errorf("failed to read CSV file: %w", err)
-		}
+	case "teams":
+		return e.sendToTeams(ctx, t, dest, result)
 
-		// Create HTTP request
-		req, err := http.NewRequestWithContext(ctx, "POST", dest.URL, bytes.NewReader(content))
-		if err != nil {
-			return fmt.Errorf("failed to create request: %w", err)
-		}
+	case "discord":
+		return e.sendToDiscord(ctx, t, dest, resultFilePath)
 
-		// Set content type
-		req.Header.Set("Content-Type", "text/csv")
-
-		// Set authentication based on token type
-		if dest.Token.Type != "" {
-			switch dest.Token.Type {
-			case "bearer":
-				req.Header.Set("Authorization", "Bearer "+dest.Token.Value)
-			case "basic":
-				req.Header.Set("Authorization", "Basic "+dest.Token.Value)
-			case "api_key":
-				req.Header.Set("X-API-Key", dest.Token.Value)
-			}
-		}
+	case "s3":
+		return e.sendToS3(ctx, t, dest, result, resultFilePath)
 
-		// Send request
-		client := &http.Client{Timeout: 30 * time.Second}
-		resp, err := client.Do(req)
-		if err != nil {
-			return fmt.Errorf("failed to send request: %w", err)
-		}
-		defer resp.Body.Close()
+	case "sftp":
+		return e.sendToSFTP(ctx, t, dest, result, resultFilePath)
 
-		if resp.StatusCode >= 300 {
-			return fmt.Errorf("received non-success status code: %d", resp.StatusCode)
-		}
+	case "file":
+		return e.sendToFile(t, dest, result, resultFilePath, run)
+
+	case "postgres":
+		return e.sendToPostgres(ctx, t, dest, result)
+
+	case "kafka":
+		return e.sendToKafka(ctx, t, dest, result, run)
+
+	case "spreadsheet":
+		return e.sendToSpreadsheet(ctx, t, dest, result)
+
+	case "custom":
+		return e.sendToCustom(ctx, t, dest, result, resultFilePath, run)
 
 	default:
 		return fmt.Errorf("destination type %s is not supported", dest.Type)
 	}
+}
 
-	return nil
+func contentType(outputFormat string) string {
+	switch outputFormat {
+	case "xlsx":
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case "json":
+		return "application/json"
+	default:
+		return "text/csv"
+	}
 }
 
-func (e *Executor) Run(ctx context.Context, t *task.Task) error {
+func (e *Executor) Run(ctx context.Context, t *task.Task) (err error) {
+	// Deferred first so queued results are retried after this run's delivery
+	defer e.retryPending(t)
+
+	run := newRunRecord(t)
+	defer func() { e.finishRun(run, err) }()
+
 	fmt.Printf("Runing task: %s\n", t.Name)
 	fmt.Printf("Database: %s\n", t.Database)
 	fmt.Printf("Query: %s\n\n", t.Query)
 
 	// Test database connection
-	dbConfig, ok := e.dbConfigs[t.Database]
-	if !ok {
-		return fmt.Errorf("database configuration not found: %s", t.Database)
-	}
-
 	fmt.Println("1. database connection...")
-	connStr := "postgres://" + dbConfig.User + ":" + dbConfig.Password + "@" + dbConfig.Host + ":5432/" + dbConfig.DBName
-
-	db, err := sql.Open("postgres", connStr)
+	db, err := e.openDatabase(t.Database)
 	if err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+		return err
 	}
 	defer db.Close()
 
@@ -316,6 +388,11 @@ func (e *Executor) Run(ctx context.Context, t *task.Task) error {
 		return fmt.Errorf("failed to get columns: %w", err)
 	}
 
+	resultColumns, err := columnsOf(rows)
+	if err != nil {
+		return err
+	}
+
 	// Validate that all requested columns exist
 	columnMap := make(map[string]bool)
 	for _, col := range columns {
@@ -342,12 +419,7 @@ func (e *Executor) Run(ctx context.Context, t *task.Task) error {
 		row := make(map[string]interface{})
 		for i, col := range columns {
 			// Only include selected columns
-			val := values[i]
-			if b, ok := val.([]byte); ok {
-				row[col] = string(b)
-			} else {
-				row[col] = val
-			}
+			row[col] = convertValue(values[i], resultColumns[i].Type)
 		}
 
 		result = append(result, row)
@@ -363,19 +435,23 @@ func (e *Executor) Run(ctx context.Context, t *task.Task) error {
 		Timestamp:     time.Now(),
 		ExecutionTime: executionTime.String(),
 		RowCount:      count,
+		Columns:       resultColumns,
 		Data:          result,
 	}
 
 	fmt.Println("\n3. destination...")
 	// Send test result to destination
-	if t.OutputFormat == "csv" {
-		if err := e.sendResultAsCSV(ctx, t, queryResult); err != nil {
-			return fmt.Errorf("failed to send to destination: %w", err)
-		}
-		fmt.Println("✓ Destination successful")
-	} else if t.OutputFormat == "json" {
-		fmt.Println("✓ Destination successful")
+	if err := e.sendResult(ctx, t, queryResult, run); err != nil {
+		return fmt.Errorf("failed to send to destination: %w", err)
+	}
+	fmt.Println("✓ Destination successful")
+	if run.Response != nil {
+		fmt.Printf("  Response: HTTP %d\n", run.Response.StatusCode)
+	}
+	if run.ReceiptID != "" {
+		fmt.Printf("  Receipt: %s\n", run.ReceiptID)
 	}
+	fmt.Printf("  Run ID: %s\n", run.ID)
 
 	return nil
 }
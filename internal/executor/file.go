@@ -0,0 +1,188 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+const defaultFileFilenameTemplate = "{{task}}_{{timestamp}}.{{ext}}"
+
+func (e *Executor) sendToFile(t *task.Task, dest destination.Destination, result QueryResult, resultFilePath string, run *RunRecord) error {
+	opts := dest.File
+	dir := expandHome(opts.Directory)
+
+	runTime := result.Timestamp.In(newValueFormatter(t).loc)
+	targetDir := dir
+	switch opts.Partition {
+	case "":
+	case "daily":
+		targetDir = filepath.Join(dir, runTime.Format("2006"), runTime.Format("01"), runTime.Format("02"))
+	case "monthly":
+		targetDir = filepath.Join(dir, runTime.Format("2006"), runTime.Format("01"))
+	default:
+		return fmt.Errorf("unsupported partition: %s", opts.Partition)
+	}
+
+	filenameTemplate := opts.FilenameTemplate
+	if filenameTemplate == "" {
+		filenameTemplate = defaultFileFilenameTemplate
+	}
+	target := filepath.Join(targetDir, expandTemplate(filenameTemplate, t, result.Timestamp))
+
+	// Templates may contain subdirectories
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(target), err)
+	}
+
+	if err := copyFile(resultFilePath, target); err != nil {
+		return err
+	}
+	fmt.Printf("Saved result to %s\n", target)
+
+	// The result is saved, so a retention problem must not fail the delivery
+	if opts.KeepLast > 0 || opts.KeepDays > 0 {
+		if err := pruneFiles(dir, filenamePattern(filenameTemplate, opts.Partition, t), opts.KeepLast, opts.KeepDays); err != nil {
+			run.warn("failed to apply retention: %v", err)
+		}
+	}
+	return nil
+}
+
+// copyFile copies src to dst through a temporary file, so readers never see a partial file
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open result file: %w", err)
+	}
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create file in %s: %w", filepath.Dir(dst), err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, in); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	return nil
+}
+
+var templatePlaceholder = regexp.MustCompile(`\{\{(task|date|timestamp|ext)\}\}`)
+
+// filenamePattern turns a filename template into a regexp matching exactly the
+// paths, relative to the destination directory and with / separators, that it
+// produces for the task
+func filenamePattern(tmpl, partition string, t *task.Task) *regexp.Regexp {
+	ext := t.OutputFormat
+	if ext == "" {
+		ext = "csv"
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	switch partition {
+	case "daily":
+		pattern.WriteString(`\d{4}/\d{2}/\d{2}/`)
+	case "monthly":
+		pattern.WriteString(`\d{4}/\d{2}/`)
+	}
+
+	last := 0
+	for _, loc := range templatePlaceholder.FindAllStringSubmatchIndex(tmpl, -1) {
+		pattern.WriteString(regexp.QuoteMeta(tmpl[last:loc[0]]))
+		switch tmpl[loc[2]:loc[3]] {
+		case "task":
+			pattern.WriteString(regexp.QuoteMeta(t.Name))
+		case "date":
+			pattern.WriteString(`\d{4}-\d{2}-\d{2}`)
+		case "timestamp":
+			pattern.WriteString(`\d{8}_\d{6}`)
+		case "ext":
+			pattern.WriteString(regexp.QuoteMeta(ext))
+		}
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(tmpl[last:]))
+	pattern.WriteString("$")
+
+	return regexp.MustCompile(pattern.String())
+}
+
+// pruneFiles removes files under dir matching pattern beyond the newest
+// keepLast, and those older than keepDays. Zero disables either rule.
+func pruneFiles(dir string, pattern *regexp.Regexp, keepLast, keepDays int) error {
+	type file struct {
+		path    string
+		modTime time.Time
+	}
+
+	var files []file
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || !pattern.MatchString(filepath.ToSlash(rel)) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, file{path: path, modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Newest first
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+
+	cutoff := time.Now().AddDate(0, 0, -keepDays)
+	for i, f := range files {
+		expired := keepDays > 0 && f.modTime.Before(cutoff)
+		excess := keepLast > 0 && i >= keepLast
+		if !expired && !excess {
+			continue
+		}
+		if err := os.Remove(f.path); err != nil {
+			return err
+		}
+		removeEmptyDirs(filepath.Dir(f.path), dir)
+	}
+	return nil
+}
+
+// removeEmptyDirs removes empty partition directories between dir and root
+func removeEmptyDirs(dir, root string) {
+	for dir != root && strings.HasPrefix(dir, root) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
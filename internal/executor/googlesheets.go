@@ -0,0 +1,171 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+)
+
+const (
+	defaultSheetsAPIBaseURL = "https://sheets.googleapis.com"
+	defaultGoogleTokenURL   = "https://oauth2.googleapis.com/token"
+	sheetsScope             = "https://www.googleapis.com/auth/spreadsheets"
+)
+
+// googleSheetsClient talks to the Google Sheets v4 values API
+type googleSheetsClient struct {
+	client        *http.Client
+	baseURL       string
+	spreadsheetID string
+}
+
+// serviceAccountKey holds the fields used from a service account JSON key
+type serviceAccountKey struct {
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+func (e *Executor) newGoogleSheetsClient(ctx context.Context, dest destination.Destination) (*googleSheetsClient, error) {
+	opts := dest.Spreadsheet
+	if opts.CredentialsFile == "" {
+		return nil, fmt.Errorf("google sheets destination requires a service account credentials file")
+	}
+
+	data, err := os.ReadFile(expandHome(opts.CredentialsFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+	var key serviceAccountKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file: %w", err)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, fmt.Errorf("credentials file is not a service account key")
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = defaultGoogleTokenURL
+	}
+
+	base, err := e.httpClient(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &jwt.Config{
+		Email:        key.ClientEmail,
+		PrivateKey:   []byte(key.PrivateKey),
+		PrivateKeyID: key.PrivateKeyID,
+		Scopes:       []string{sheetsScope},
+		TokenURL:     key.TokenURI,
+	}
+	client := config.Client(context.WithValue(ctx, oauth2.HTTPClient, base))
+	client.Timeout = base.Timeout
+
+	baseURL := opts.APIBaseURL
+	if baseURL == "" {
+		baseURL = defaultSheetsAPIBaseURL
+	}
+
+	return &googleSheetsClient{
+		client:        client,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		spreadsheetID: opts.SpreadsheetID,
+	}, nil
+}
+
+func (c *googleSheetsClient) Get(ctx context.Context, rangeA1 string) ([][]interface{}, error) {
+	var values sheetValues
+	if err := c.do(ctx, http.MethodGet, c.valuesURL(rangeA1, "", nil), nil, &values); err != nil {
+		return nil, err
+	}
+	return values.Values, nil
+}
+
+func (c *googleSheetsClient) Clear(ctx context.Context, rangeA1 string) error {
+	return c.do(ctx, http.MethodPost, c.valuesURL(rangeA1, ":clear", nil), struct{}{}, nil)
+}
+
+func (c *googleSheetsClient) Update(ctx context.Context, rangeA1 string, rows [][]interface{}) error {
+	query := url.Values{"valueInputOption": {"RAW"}}
+	return c.do(ctx, http.MethodPut, c.valuesURL(rangeA1, "", query), sheetValues{Range: rangeA1, Values: rows}, nil)
+}
+
+func (c *googleSheetsClient) Append(ctx context.Context, rangeA1 string, rows [][]interface{}) error {
+	query := url.Values{"valueInputOption": {"RAW"}, "insertDataOption": {"INSERT_ROWS"}}
+	return c.do(ctx, http.MethodPost, c.valuesURL(rangeA1, ":append", query), sheetValues{Range: rangeA1, Values: rows}, nil)
+}
+
+type sheetValues struct {
+	Range  string          `json:"range"`
+	Values [][]interface{} `json:"values,omitempty"`
+}
+
+func (c *googleSheetsClient) valuesURL(rangeA1, action string, query url.Values) string {
+	u := fmt.Sprintf("%s/v4/spreadsheets/%s/values/%s%s",
+		c.baseURL, url.PathEscape(c.spreadsheetID), url.PathEscape(rangeA1), action)
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// do calls the API with payload as the JSON body, if any, and decodes the
+// response into out, if given
+func (c *googleSheetsClient) do(ctx context.Context, method, endpoint string, payload, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode sheet values: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		err = fmt.Errorf("failed to call Google Sheets API: %w", err)
+		var tokenErr *oauth2.RetrieveError
+		if errors.As(err, &tokenErr) && tokenErr.Response != nil && retryableStatus(tokenErr.Response.StatusCode) {
+			return retryable(err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseCapture))
+		err := fmt.Errorf("google sheets API error (status %d): %s", resp.StatusCode, responseSnippet(respBody))
+		if retryableStatus(resp.StatusCode) {
+			return retryable(err)
+		}
+		return err
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out); err != nil {
+			return fmt.Errorf("failed to parse Google Sheets API response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package executor
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+type sheetsCall struct {
+	method string
+	path   string // unescaped, including the query
+	auth   string
+	body   sheetValues
+}
+
+// sheetsStub serves the service account token endpoint and the values API
+type sheetsStub struct {
+	*httptest.Server
+	mu       sync.Mutex
+	calls    []sheetsCall
+	firstRow string // JSON values returned for reads
+	status   int    // status of values API calls, 200 if zero
+}
+
+func newSheetsStub(t *testing.T) *sheetsStub {
+	stub := &sheetsStub{}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if err := r.ParseForm(); err != nil || r.PostForm.Get("assertion") == "" {
+				t.Errorf("token request without assertion")
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"sheets-token","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+
+		call := sheetsCall{method: r.Method, path: r.URL.Path, auth: r.Header.Get("Authorization")}
+		if r.URL.RawQuery != "" {
+			call.path += "?" + r.URL.RawQuery
+		}
+		data, _ := io.ReadAll(r.Body)
+		if len(data) > 0 {
+			decoder := json.NewDecoder(strings.NewReader(string(data)))
+			decoder.UseNumber()
+			if err := decoder.Decode(&call.body); err != nil {
+				t.Errorf("failed to decode %s body: %v", r.URL.Path, err)
+			}
+		}
+
+		stub.mu.Lock()
+		stub.calls = append(stub.calls, call)
+		status, firstRow := stub.status, stub.firstRow
+		stub.mu.Unlock()
+
+		if status != 0 {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"error":{"message":"backend unavailable"}}`))
+			return
+		}
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"range":"Sheet1!A1:Z1","majorDimension":"ROWS"` + firstRow + `}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	return stub
+}
+
+func (s *sheetsStub) recorded() []sheetsCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]sheetsCall(nil), s.calls...)
+}
+
+func serviceAccountFile(t *testing.T, tokenURL string) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	data, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "goractor@example.iam.gserviceaccount.com",
+		"private_key":  string(pemKey),
+		"token_uri":    tokenURL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "service-account.json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func sheetsDestination(t *testing.T, stub *sheetsStub, mode string) destination.Destination {
+	return destination.Destination{
+		Type: "spreadsheet",
+		Spreadsheet: destination.SpreadsheetOptions{
+			SpreadsheetID:   "sheet-123",
+			Sheet:           "Daily Sales",
+			Mode:            mode,
+			CredentialsFile: serviceAccountFile(t, stub.URL+"/token"),
+			APIBaseURL:      stub.URL,
+		},
+	}
+}
+
+func TestSendToSpreadsheetReplace(t *testing.T) {
+	stub := newSheetsStub(t)
+	defer stub.Close()
+
+	e := &Executor{}
+	result := testResult(2)
+	result.Data[1]["name"] = nil
+	if err := e.sendToSpreadsheet(context.Background(), &task.Task{Name: "t"}, sheetsDestination(t, stub, "replace"), result); err != nil {
+		t.Fatalf("sendToSpreadsheet error: %v", err)
+	}
+
+	calls := stub.recorded()
+	if len(calls) != 2 {
+		t.Fatalf("got %d API calls, want clear and update: %+v", len(calls), calls)
+	}
+	if calls[0].method != http.MethodPost || calls[0].path != "/v4/spreadsheets/sheet-123/values/'Daily Sales':clear" {
+		t.Errorf("clear call = %s %s", calls[0].method, calls[0].path)
+	}
+	if calls[1].method != http.MethodPut || calls[1].path != "/v4/spreadsheets/sheet-123/values/'Daily Sales'!A1?valueInputOption=RAW" {
+		t.Errorf("update call = %s %s", calls[1].method, calls[1].path)
+	}
+	for _, call := range calls {
+		if call.auth != "Bearer sheets-token" {
+			t.Errorf("%s Authorization = %q", call.path, call.auth)
+		}
+	}
+
+	want := [][]interface{}{
+		{"id", "name"},
+		{json.Number("1"), "row 1"},
+		{json.Number("2"), ""},
+	}
+	if got, _ := json.Marshal(calls[1].body.Values); string(got) != mustJSON(t, want) {
+		t.Errorf("values = %s, want %s", got, mustJSON(t, want))
+	}
+}
+
+func TestSendToSpreadsheetAppend(t *testing.T) {
+	tests := []struct {
+		name       string
+		firstRow   string
+		skipHeader bool
+		wantRows   int
+		wantReads  int
+	}{
+		{name: "empty sheet gets a header", firstRow: "", wantRows: 3, wantReads: 1},
+		{name: "existing header is not repeated", firstRow: `,"values":[["id","name"]]`, wantRows: 2, wantReads: 1},
+		{name: "skip header does not read", skipHeader: true, wantRows: 2, wantReads: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newSheetsStub(t)
+			defer stub.Close()
+			stub.mu.Lock()
+			stub.firstRow = tt.firstRow
+			stub.mu.Unlock()
+
+			dest := sheetsDestination(t, stub, "append")
+			dest.Spreadsheet.SkipHeader = tt.skipHeader
+			e := &Executor{}
+			if err := e.sendToSpreadsheet(context.Background(), &task.Task{Name: "t"}, dest, testResult(2)); err != nil {
+				t.Fatalf("sendToSpreadsheet error: %v", err)
+			}
+
+			calls := stub.recorded()
+			reads := 0
+			var appendCall *sheetsCall
+			for i, call := range calls {
+				switch call.method {
+				case http.MethodGet:
+					reads++
+					if call.path != "/v4/spreadsheets/sheet-123/values/'Daily Sales'!1:1" {
+						t.Errorf("read path = %s", call.path)
+					}
+				case http.MethodPost:
+					appendCall = &calls[i]
+				}
+			}
+			if reads != tt.wantReads {
+				t.Errorf("got %d reads, want %d", reads, tt.wantReads)
+			}
+			if appendCall == nil {
+				t.Fatal("no append call")
+			}
+			if appendCall.path != "/v4/spreadsheets/sheet-123/values/'Daily Sales'!A1:append?insertDataOption=INSERT_ROWS&valueInputOption=RAW" {
+				t.Errorf("append path = %s", appendCall.path)
+			}
+			if len(appendCall.body.Values) != tt.wantRows {
+				t.Errorf("appended %d rows, want %d", len(appendCall.body.Values), tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestSendToSpreadsheetErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		retryable bool
+	}{
+		{name: "permission denied", status: http.StatusForbidden, retryable: false},
+		{name: "unavailable", status: http.StatusServiceUnavailable, retryable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newSheetsStub(t)
+			defer stub.Close()
+			stub.mu.Lock()
+			stub.status = tt.status
+			stub.mu.Unlock()
+
+			e := &Executor{}
+			err := e.sendToSpreadsheet(context.Background(), &task.Task{Name: "t"}, sheetsDestination(t, stub, "replace"), testResult(1))
+			if err == nil {
+				t.Fatal("sendToSpreadsheet succeeded, want error")
+			}
+			if !strings.Contains(err.Error(), "backend unavailable") {
+				t.Errorf("error %q does not include the API message", err)
+			}
+			if isRetryable(err) != tt.retryable {
+				t.Errorf("isRetryable(%v) = %v, want %v", err, !tt.retryable, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestQuoteSheetName(t *testing.T) {
+	tests := map[string]string{
+		"Sheet1":       "'Sheet1'",
+		"Daily Sales":  "'Daily Sales'",
+		"Bob's report": "'Bob''s report'",
+	}
+	for name, want := range tests {
+		if got := quoteSheetName(name); got != want {
+			t.Errorf("quoteSheetName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+)
+
+const (
+	defaultHMACSignatureHeader = "X-Signature"
+	defaultHMACTimestampHeader = "X-Timestamp"
+	defaultHMACTolerance       = 300 * time.Second
+)
+
+// signRequest adds a timestamp header and an HMAC signature over
+// "<timestamp>.<body>" in hex
+func signRequest(req *http.Request, token destination.TokenConfig, body []byte, now time.Time) error {
+	opts := token.HMAC
+
+	var newHash func() hash.Hash
+	switch opts.Algorithm {
+	case "", "sha256":
+		newHash = sha256.New
+	case "sha512":
+		newHash = sha512.New
+	default:
+		return fmt.Errorf("unsupported HMAC algorithm: %s", opts.Algorithm)
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(newHash, []byte(token.Value))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	signature := opts.SignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+
+	signatureHeader := opts.SignatureHeader
+	if signatureHeader == "" {
+		signatureHeader = defaultHMACSignatureHeader
+	}
+	timestampHeader := opts.TimestampHeader
+	if timestampHeader == "" {
+		timestampHeader = defaultHMACTimestampHeader
+	}
+
+	req.Header.Set(timestampHeader, timestamp)
+	req.Header.Set(signatureHeader, signature)
+	return nil
+}
+
+// hmacTolerance returns how old a signature the receiver accepts. Signed
+// requests that do not complete within it are abandoned.
+func hmacTolerance(opts destination.HMACConfig) time.Duration {
+	if opts.ToleranceSeconds > 0 {
+		return time.Duration(opts.ToleranceSeconds) * time.Second
+	}
+	return defaultHMACTolerance
+}
+
+// hmacSkewHint explains a rejected signed request when the server clock is
+// further from the signing time than the receiver tolerates
+func hmacSkewHint(resp *http.Response, opts destination.HMACConfig, signedAt time.Time) string {
+	if resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden {
+		return ""
+	}
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return ""
+	}
+
+	tolerance := hmacTolerance(opts)
+	skew := serverTime.Sub(signedAt)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew <= tolerance {
+		return ""
+	}
+	return fmt.Sprintf("local clock differs from the server by %s, more than the %s signature tolerance",
+		skew.Round(time.Second), tolerance)
+}
@@ -0,0 +1,108 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+func TestSignRequest(t *testing.T) {
+	signedAt := time.Unix(1700000000, 0)
+	body := []byte(`{"id":1}`)
+
+	tests := []struct {
+		name          string
+		opts          destination.HMACConfig
+		body          []byte
+		wantHeader    string
+		wantSignature string
+	}{
+		{
+			name:          "sha256",
+			body:          body,
+			wantHeader:    "X-Signature",
+			wantSignature: "6ad987a0e3b5314aaa89cb7982bec9de372d88f4364c8a532487a1c34c87007f",
+		},
+		{
+			name:          "sha512 with prefix and custom header",
+			opts:          destination.HMACConfig{Algorithm: "sha512", SignatureHeader: "X-Hub-Signature", SignaturePrefix: "sha512="},
+			body:          body,
+			wantHeader:    "X-Hub-Signature",
+			wantSignature: "sha512=63f3d66fa3e67458c94e91e37696a8657b1d222b649efd72d5770af78004dd3c21d4e4af46f391f1b17b9ca425eb2a56dd13e9fbd2c2bcea5ad2b72791a00fb3",
+		},
+		{
+			name:          "empty body",
+			wantHeader:    "X-Signature",
+			wantSignature: "afff58f275d9dc5a6838bf4d5edf7064da0cf4f51c525d6db3f454eab0002933",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "http://example.com", nil)
+			token := destination.TokenConfig{Type: "hmac", Value: "shared-secret", HMAC: tt.opts}
+			if err := signRequest(req, token, tt.body, signedAt); err != nil {
+				t.Fatalf("signRequest error: %v", err)
+			}
+			if got := req.Header.Get("X-Timestamp"); got != "1700000000" {
+				t.Errorf("timestamp = %q", got)
+			}
+			if got := req.Header.Get(tt.wantHeader); got != tt.wantSignature {
+				t.Errorf("%s = %q, want %q", tt.wantHeader, got, tt.wantSignature)
+			}
+		})
+	}
+}
+
+func TestSignRequestUnsupportedAlgorithm(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "http://example.com", nil)
+	token := destination.TokenConfig{Type: "hmac", Value: "s", HMAC: destination.HMACConfig{Algorithm: "md5"}}
+	if err := signRequest(req, token, nil, time.Now()); err == nil {
+		t.Error("signRequest accepted md5")
+	}
+}
+
+func TestHMACToleranceEnforced(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Answer only after the signature has expired
+		io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	dest := destination.Destination{
+		Type: "custom",
+		URL:  server.URL,
+		Token: destination.TokenConfig{
+			Type:  "hmac",
+			Value: "shared-secret",
+			HMAC:  destination.HMACConfig{ToleranceSeconds: 1},
+		},
+	}
+
+	e := &Executor{}
+	started := time.Now()
+	err := e.sendToCustom(context.Background(), &task.Task{Name: "t"}, dest, testResult(1), writeResultFile(t), &RunRecord{})
+	if err == nil {
+		t.Fatal("sendToCustom succeeded, want error")
+	}
+	if elapsed := time.Since(started); elapsed > 3*time.Second {
+		t.Errorf("request ran for %s despite the 1s tolerance", elapsed)
+	}
+	if !strings.Contains(err.Error(), "signature tolerance") {
+		t.Errorf("error %q does not mention the tolerance", err)
+	}
+	if !isRetryable(err) {
+		t.Error("an expired signature should be queued to be signed again")
+	}
+}
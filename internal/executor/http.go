@@ -0,0 +1,110 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+)
+
+// httpClient returns the client used to call HTTP based destinations,
+// configured with the destination's TLS and proxy settings
+func (e *Executor) httpClient(dest destination.Destination) (*http.Client, error) {
+	opts := dest.HTTP
+
+	tlsConfig, err := tlsConfig(opts.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: transport,
+	}, nil
+}
+
+func tlsConfig(opts destination.TLSOptions) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: opts.ServerName,
+	}
+
+	switch opts.MinVersion {
+	case "", "1.2":
+	case "1.3":
+		config.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported minimum TLS version: %s", opts.MinVersion)
+	}
+
+	if opts.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(expandHome(opts.CAFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", opts.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		if opts.CertFile == "" || opts.KeyFile == "" {
+			return nil, fmt.Errorf("client certificate and key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(expandHome(opts.CertFile), expandHome(opts.KeyFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// authorizeRequest sets the authentication headers of the token type. Signed
+// requests cover the body, so it must be the exact payload being sent.
+func (e *Executor) authorizeRequest(ctx context.Context, req *http.Request, dest destination.Destination, body []byte, now time.Time) error {
+	token := dest.Token
+	switch token.Type {
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+token.Value)
+	case "basic":
+		req.Header.Set("Authorization", "Basic "+token.Value)
+	case "api_key":
+		req.Header.Set("X-API-Key", token.Value)
+	case "hmac":
+		return signRequest(req, token, body, now)
+	case "oauth2":
+		client, err := e.httpClient(dest)
+		if err != nil {
+			return err
+		}
+		accessToken, err := e.oauth2Tokens.token(ctx, client, token)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken.accessToken)
+	}
+	return nil
+}
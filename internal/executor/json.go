@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+// orderedRow marshals a row as a JSON object keeping the column order
+type orderedRow struct {
+	keys   []string
+	values []interface{}
+}
+
+func (r orderedRow) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range r.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(r.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (e *Executor) createJSONFile(t *task.Task, result QueryResult) (string, error) {
+	headers := resultHeaders(result, t.Columns)
+	formatter := newValueFormatter(t)
+
+	rows := make([]orderedRow, 0, len(result.Data))
+	for _, row := range result.Data {
+		values := make([]interface{}, len(headers))
+		for i, header := range headers {
+			values[i] = formatter.JSON(row[header], result.ColumnType(header))
+		}
+		rows = append(rows, orderedRow{keys: headers, values: values})
+	}
+
+	output := struct {
+		TaskID        string       `json:"task_id"`
+		Timestamp     string       `json:"timestamp"`
+		ExecutionTime string       `json:"execution_time"`
+		RowCount      int          `json:"row_count"`
+		Columns       []Column     `json:"columns"`
+		Data          []orderedRow `json:"data"`
+	}{
+		TaskID:        result.TaskID,
+		Timestamp:     result.Timestamp.In(formatter.loc).Format(time.RFC3339),
+		ExecutionTime: result.ExecutionTime,
+		RowCount:      result.RowCount,
+		Columns:       result.Columns,
+		Data:          rows,
+	}
+
+	data, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	tmpDir := filepath.Join(os.TempDir(), "goractor")
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	timestamp := time.Now().Format("20060102_150405")
+	filename := filepath.Join(tmpDir, fmt.Sprintf("%s_%s.json", result.TaskID, timestamp))
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write JSON file: %w", err)
+	}
+
+	return filename, nil
+}
@@ -0,0 +1,190 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/task"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+func (e *Executor) sendToKafka(ctx context.Context, t *task.Task, dest destination.Destination, result QueryResult, run *RunRecord) error {
+	opts := dest.Kafka
+	if len(opts.Brokers) == 0 || opts.Topic == "" {
+		return fmt.Errorf("kafka destination requires brokers and a topic")
+	}
+
+	messages, err := kafkaMessages(t, opts, result, run)
+	if err != nil {
+		return err
+	}
+
+	// The writer does not close a transport it was given
+	transport := &kafka.Transport{}
+	defer transport.CloseIdleConnections()
+	if opts.SASL.Mechanism != "" {
+		mechanism, err := kafkaSASL(opts.SASL)
+		if err != nil {
+			return err
+		}
+		transport.SASL = mechanism
+	}
+	if opts.TLS != nil {
+		config, err := tlsConfig(*opts.TLS)
+		if err != nil {
+			return err
+		}
+		transport.TLS = config
+	}
+
+	acks := kafka.RequireAll
+	switch opts.Acks {
+	case "", "all":
+	case "one":
+		acks = kafka.RequireOne
+	case "none":
+		acks = kafka.RequireNone
+	default:
+		return fmt.Errorf("unsupported kafka acks level: %s", opts.Acks)
+	}
+
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(opts.Brokers...),
+		Topic:        opts.Topic,
+		Balancer:     &kafka.Hash{}, // same key, same partition
+		RequiredAcks: acks,
+		BatchTimeout: 10 * time.Millisecond,
+		Transport:    transport,
+	}
+	defer writer.Close()
+
+	if err := writer.WriteMessages(ctx, messages...); err != nil {
+		publishErr := fmt.Errorf("failed to publish to kafka topic %s: %w", opts.Topic, err)
+		// Dial errors and timeouts come back as is; per-message errors as
+		// WriteErrors, where the whole result is retried if any message can be
+		if isRetryable(err) {
+			return retryable(publishErr)
+		}
+		var writeErrs kafka.WriteErrors
+		if errors.As(err, &writeErrs) {
+			for _, writeErr := range writeErrs {
+				if writeErr != nil && isRetryable(writeErr) {
+					return retryable(publishErr)
+				}
+			}
+		}
+		return publishErr
+	}
+
+	fmt.Printf("Published %d messages to kafka topic %s\n", len(messages), opts.Topic)
+	return nil
+}
+
+// kafkaMessages encodes the result as one JSON message per row, or as JSON
+// documents of up to BatchSize rows in batch mode
+func kafkaMessages(t *task.Task, opts destination.KafkaOptions, result QueryResult, run *RunRecord) ([]kafka.Message, error) {
+	headers := resultHeaders(result, t.Columns)
+	formatter := newValueFormatter(t)
+	runTime := result.Timestamp.In(formatter.loc).Format(time.RFC3339)
+
+	metadata := []kafka.Header{
+		{Key: "goractor-task", Value: []byte(t.Name)},
+		{Key: "goractor-run-id", Value: []byte(run.ID)},
+		{Key: "goractor-run-time", Value: []byte(runTime)},
+		{Key: "goractor-row-count", Value: []byte(strconv.Itoa(result.RowCount))},
+		{Key: "content-type", Value: []byte("application/json")},
+	}
+
+	rows := make([]orderedRow, 0, len(result.Data))
+	for _, row := range result.Data {
+		values := make([]interface{}, len(headers))
+		for i, header := range headers {
+			values[i] = formatter.JSON(row[header], result.ColumnType(header))
+		}
+		rows = append(rows, orderedRow{keys: headers, values: values})
+	}
+
+	var messages []kafka.Message
+	switch opts.Mode {
+	case "", "row":
+		for i, row := range rows {
+			value, err := json.Marshal(row)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode row: %w", err)
+			}
+			msg := kafka.Message{Value: value, Headers: metadata}
+			if opts.KeyColumn != "" {
+				v := result.Data[i][opts.KeyColumn]
+				if v != nil {
+					msg.Key = []byte(formatter.Text(v, result.ColumnType(opts.KeyColumn)))
+				}
+			}
+			messages = append(messages, msg)
+		}
+
+	case "batch":
+		size := opts.BatchSize
+		if size <= 0 {
+			size = len(rows)
+		}
+		for start := 0; start < len(rows); start += size {
+			end := start + size
+			if end > len(rows) {
+				end = len(rows)
+			}
+			value, err := json.Marshal(struct {
+				TaskID    string       `json:"task_id"`
+				RunID     string       `json:"run_id"`
+				Timestamp string       `json:"timestamp"`
+				RowCount  int          `json:"row_count"`
+				Columns   []Column     `json:"columns"`
+				Data      []orderedRow `json:"data"`
+			}{
+				TaskID:    t.Name,
+				RunID:     run.ID,
+				Timestamp: runTime,
+				RowCount:  end - start,
+				Columns:   result.Columns,
+				Data:      rows[start:end],
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode batch: %w", err)
+			}
+			messages = append(messages, kafka.Message{Key: []byte(t.Name), Value: value, Headers: metadata})
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported kafka mode: %s", opts.Mode)
+	}
+
+	return messages, nil
+}
+
+func kafkaSASL(opts destination.KafkaSASL) (sasl.Mechanism, error) {
+	switch opts.Mechanism {
+	case "plain":
+		return plain.Mechanism{Username: opts.Username, Password: opts.Password}, nil
+	case "scram-sha-256":
+		mechanism, err := scram.Mechanism(scram.SHA256, opts.Username, opts.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up SASL: %w", err)
+		}
+		return mechanism, nil
+	case "scram-sha-512":
+		mechanism, err := scram.Mechanism(scram.SHA512, opts.Username, opts.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up SASL: %w", err)
+		}
+		return mechanism, nil
+	default:
+		return nil, fmt.Errorf("unsupported SASL mechanism: %s", opts.Mechanism)
+	}
+}
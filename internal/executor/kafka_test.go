@@ -0,0 +1,37 @@
+package executor
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+func TestSendToKafkaUnreachableBroker(t *testing.T) {
+	// Reserve a port and close it so nothing is listening there
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	dest := destination.Destination{
+		Type:  "kafka",
+		Kafka: destination.KafkaOptions{Brokers: []string{addr}, Topic: "reports"},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	e := &Executor{}
+	err = e.sendToKafka(ctx, &task.Task{Name: "t"}, dest, testResult(1), &RunRecord{ID: "run-1"})
+	if err == nil {
+		t.Fatal("sendToKafka succeeded, want error")
+	}
+	if !isRetryable(err) {
+		t.Errorf("an unreachable broker should be queued for retry: %v", err)
+	}
+}
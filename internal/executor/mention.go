@@ -0,0 +1,114 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ONCALLJP/goractor/internal/task"
+	"github.com/slack-go/slack"
+)
+
+// matchMentions returns the mentions of all rules whose conditions match the result
+func matchMentions(t *task.Task, result QueryResult) ([]string, error) {
+	formatter := newValueFormatter(t)
+
+	var mentions []string
+	for _, rule := range t.Slack.Mentions {
+		matched, err := ruleMatches(rule, result, formatter)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			mentions = append(mentions, rule.Mention)
+		}
+	}
+	return mentions, nil
+}
+
+func ruleMatches(rule task.MentionRule, result QueryResult, formatter valueFormatter) (bool, error) {
+	if rule.MinRows > 0 && result.RowCount < rule.MinRows {
+		return false, nil
+	}
+	if rule.Column == "" {
+		return true, nil
+	}
+
+	var pattern *regexp.Regexp
+	if rule.Matches != "" {
+		var err error
+		pattern, err = regexp.Compile(rule.Matches)
+		if err != nil {
+			return false, fmt.Errorf("invalid mention pattern %q: %w", rule.Matches, err)
+		}
+	}
+
+	// The rule matches if any row has a matching value
+	columnType := result.ColumnType(rule.Column)
+	for _, row := range result.Data {
+		v, ok := row[rule.Column]
+		if !ok {
+			continue
+		}
+		value := formatter.Text(v, columnType)
+		if pattern != nil && !pattern.MatchString(value) {
+			continue
+		}
+		if rule.Equals != "" && value != rule.Equals {
+			continue
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// slackMention converts a configured mention into Slack's mention syntax.
+// @here, @channel and @everyone are special mentions, other @handles are
+// looked up as user groups when a bot client is available. Mentions already
+// in Slack syntax such as <@U0123> or <!subteam^S0123> are used as is.
+func slackMention(ctx context.Context, api *slack.Client, mention string) string {
+	if !strings.HasPrefix(mention, "@") {
+		return mention
+	}
+
+	handle := strings.TrimPrefix(mention, "@")
+	switch handle {
+	case "here", "channel", "everyone":
+		return "<!" + handle + ">"
+	}
+
+	if api != nil {
+		groups, err := api.GetUserGroupsContext(ctx)
+		if err != nil {
+			fmt.Printf("Failed to look up slack user group %s: %v\n", mention, err)
+			return mention
+		}
+		for _, group := range groups {
+			if group.Handle == handle {
+				return "<!subteam^" + group.ID + ">"
+			}
+		}
+	}
+	return mention
+}
+
+// withMentions returns a copy of the task whose message is prefixed with the
+// mentions of all matching rules
+func withMentions(ctx context.Context, api *slack.Client, t *task.Task, result QueryResult) (*task.Task, error) {
+	mentions, err := matchMentions(t, result)
+	if err != nil {
+		return nil, err
+	}
+	if len(mentions) == 0 {
+		return t, nil
+	}
+
+	for i, mention := range mentions {
+		mentions[i] = slackMention(ctx, api, mention)
+	}
+
+	mentioned := *t
+	mentioned.Message = strings.Join(mentions, " ") + " " + t.Message
+	return &mentioned, nil
+}
@@ -0,0 +1,133 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+)
+
+// oauth2ExpiryMargin renews tokens this long before they expire
+const oauth2ExpiryMargin = 60 * time.Second
+
+type oauth2Token struct {
+	accessToken string
+	expiry      time.Time // zero if the server did not say
+}
+
+func (t oauth2Token) valid(now time.Time) bool {
+	return t.accessToken != "" && (t.expiry.IsZero() || now.Add(oauth2ExpiryMargin).Before(t.expiry))
+}
+
+// oauth2Cache keeps client credentials tokens for the lifetime of the executor
+type oauth2Cache struct {
+	mu     sync.Mutex
+	tokens map[string]oauth2Token
+}
+
+func newOAuth2Cache() *oauth2Cache {
+	return &oauth2Cache{tokens: make(map[string]oauth2Token)}
+}
+
+func oauth2CacheKey(opts destination.OAuth2Config) string {
+	return strings.Join([]string{opts.TokenURL, opts.ClientID, opts.Audience, strings.Join(opts.Scopes, " ")}, "\x00")
+}
+
+// token returns a cached token or fetches a new one
+func (c *oauth2Cache) token(ctx context.Context, client *http.Client, token destination.TokenConfig) (oauth2Token, error) {
+	key := oauth2CacheKey(token.OAuth2)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.tokens[key]; ok && cached.valid(time.Now()) {
+		return cached, nil
+	}
+
+	fetched, err := fetchOAuth2Token(ctx, client, token)
+	if err != nil {
+		return oauth2Token{}, err
+	}
+	c.tokens[key] = fetched
+	return fetched, nil
+}
+
+// invalidate drops a cached token, e.g. after the API rejected it
+func (c *oauth2Cache) invalidate(token destination.TokenConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.tokens, oauth2CacheKey(token.OAuth2))
+}
+
+// fetchOAuth2Token runs the client credentials grant against the token endpoint
+func fetchOAuth2Token(ctx context.Context, client *http.Client, token destination.TokenConfig) (oauth2Token, error) {
+	opts := token.OAuth2
+	if opts.TokenURL == "" {
+		return oauth2Token{}, fmt.Errorf("oauth2 token URL is not configured")
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(opts.Scopes) > 0 {
+		form.Set("scope", strings.Join(opts.Scopes, " "))
+	}
+	if opts.Audience != "" {
+		form.Set("audience", opts.Audience)
+	}
+	if opts.AuthStyle == "body" {
+		form.Set("client_id", opts.ClientID)
+		form.Set("client_secret", token.Value)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, opts.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oauth2Token{}, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if opts.AuthStyle != "body" {
+		req.SetBasicAuth(url.QueryEscape(opts.ClientID), url.QueryEscape(token.Value))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return oauth2Token{}, fmt.Errorf("failed to request oauth2 token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return oauth2Token{}, fmt.Errorf("failed to read oauth2 token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("oauth2 token request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		if retryableStatus(resp.StatusCode) {
+			return oauth2Token{}, retryable(err)
+		}
+		return oauth2Token{}, err
+	}
+
+	var tokenResp struct {
+		AccessToken string      `json:"access_token"`
+		ExpiresIn   json.Number `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return oauth2Token{}, fmt.Errorf("failed to parse oauth2 token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return oauth2Token{}, fmt.Errorf("oauth2 token response has no access_token")
+	}
+
+	result := oauth2Token{accessToken: tokenResp.AccessToken}
+	if seconds, err := tokenResp.ExpiresIn.Int64(); err == nil && seconds > 0 {
+		result.expiry = time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	return result, nil
+}
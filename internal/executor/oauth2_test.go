@@ -0,0 +1,181 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+// tokenServer issues tok-1, tok-2, ... and checks the client credentials
+func tokenServer(t *testing.T, expiresIn int, issued *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("failed to parse token request: %v", err)
+		}
+		if grant := r.PostForm.Get("grant_type"); grant != "client_credentials" {
+			t.Errorf("grant_type = %q", grant)
+		}
+		if scope := r.PostForm.Get("scope"); scope != "reports.write" {
+			t.Errorf("scope = %q", scope)
+		}
+		if id, secret, ok := r.BasicAuth(); !ok || id != "goractor" || secret != "s3cret" {
+			t.Errorf("basic auth = %q %q %v", id, secret, ok)
+		}
+
+		n := atomic.AddInt32(issued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"tok-%d","token_type":"Bearer","expires_in":%d}`, n, expiresIn)
+	}))
+}
+
+func oauth2Destination(tokenURL, apiURL string) destination.Destination {
+	return destination.Destination{
+		Type: "custom",
+		URL:  apiURL,
+		Token: destination.TokenConfig{
+			Type:  "oauth2",
+			Value: "s3cret",
+			OAuth2: destination.OAuth2Config{
+				TokenURL: tokenURL,
+				ClientID: "goractor",
+				Scopes:   []string{"reports.write"},
+			},
+		},
+	}
+}
+
+func writeResultFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "result.csv")
+	if err := os.WriteFile(path, []byte("id\n1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestOAuth2TokenCached(t *testing.T) {
+	var issued int32
+	tokens := tokenServer(t, 3600, &issued)
+	defer tokens.Close()
+
+	var mu sync.Mutex
+	var authHeaders []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+	}))
+	defer api.Close()
+
+	e := &Executor{oauth2Tokens: newOAuth2Cache()}
+	dest := oauth2Destination(tokens.URL, api.URL)
+	path := writeResultFile(t)
+
+	for i := 0; i < 3; i++ {
+		run := &RunRecord{}
+		if err := e.sendToCustom(context.Background(), &task.Task{Name: "t"}, dest, testResult(1), path, run); err != nil {
+			t.Fatalf("send %d: %v", i, err)
+		}
+	}
+
+	if n := atomic.LoadInt32(&issued); n != 1 {
+		t.Errorf("issued %d tokens, want 1", n)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for i, header := range authHeaders {
+		if header != "Bearer tok-1" {
+			t.Errorf("request %d Authorization = %q", i, header)
+		}
+	}
+}
+
+func TestOAuth2TokenRenewedBeforeExpiry(t *testing.T) {
+	var issued int32
+	// Expires within the renewal margin, so every use fetches a new token
+	tokens := tokenServer(t, 30, &issued)
+	defer tokens.Close()
+
+	cache := newOAuth2Cache()
+	dest := oauth2Destination(tokens.URL, "")
+	for i := 1; i <= 2; i++ {
+		token, err := cache.token(context.Background(), http.DefaultClient, dest.Token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("tok-%d", i); token.accessToken != want {
+			t.Errorf("token = %s, want %s", token.accessToken, want)
+		}
+		if !token.expiry.After(time.Now()) {
+			t.Errorf("expiry %v is not in the future", token.expiry)
+		}
+	}
+}
+
+func TestOAuth2RetryAfterUnauthorized(t *testing.T) {
+	var issued int32
+	tokens := tokenServer(t, 3600, &issued)
+	defer tokens.Close()
+
+	var calls int32
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		// The first token has been revoked
+		if r.Header.Get("Authorization") != "Bearer tok-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id":"r-1"}`))
+	}))
+	defer api.Close()
+
+	e := &Executor{oauth2Tokens: newOAuth2Cache()}
+	dest := oauth2Destination(tokens.URL, api.URL)
+	dest.HTTP.ReceiptPath = "$.id"
+	run := &RunRecord{}
+
+	if err := e.sendToCustom(context.Background(), &task.Task{Name: "t"}, dest, testResult(1), writeResultFile(t), run); err != nil {
+		t.Fatalf("sendToCustom error: %v", err)
+	}
+	if n, c := atomic.LoadInt32(&issued), atomic.LoadInt32(&calls); n != 2 || c != 2 {
+		t.Errorf("issued %d tokens and made %d calls, want 2 and 2", n, c)
+	}
+	if run.ReceiptID != "r-1" {
+		t.Errorf("receipt = %q, want r-1", run.ReceiptID)
+	}
+}
+
+func TestOAuth2RetriesOnlyOnce(t *testing.T) {
+	var issued int32
+	tokens := tokenServer(t, 3600, &issued)
+	defer tokens.Close()
+
+	var calls int32
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer api.Close()
+
+	e := &Executor{oauth2Tokens: newOAuth2Cache()}
+	err := e.sendToCustom(context.Background(), &task.Task{Name: "t"}, oauth2Destination(tokens.URL, api.URL), testResult(1), writeResultFile(t), &RunRecord{})
+	if err == nil {
+		t.Fatal("sendToCustom succeeded, want error")
+	}
+	if c := atomic.LoadInt32(&calls); c != 2 {
+		t.Errorf("made %d calls, want 2", c)
+	}
+	if isRetryable(err) {
+		t.Error("a rejected token should not be queued for retry")
+	}
+}
@@ -0,0 +1,403 @@
+package executor
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+const (
+	defaultOutboxTTL = 72 * time.Hour
+	maxOutboxBackoff = time.Hour
+	// outboxRetryTimeout bounds the redelivery done after a task run
+	outboxRetryTimeout = 30 * time.Second
+
+	outboxItemFile = "item.json"
+)
+
+// OutboxItem is an undelivered result kept under <stateDir>/outbox/<id>/
+// together with the task snapshot, the query result and the rendered file
+type OutboxItem struct {
+	ID          string    `json:"id"`
+	Task        string    `json:"task"`
+	Destination string    `json:"destination"`
+	RunID       string    `json:"run_id"`
+	File        string    `json:"file"` // name of the rendered result file
+	CreatedAt   time.Time `json:"created_at"`
+	LastAttempt time.Time `json:"last_attempt"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error"`
+}
+
+// nextAttempt backs off exponentially from one minute up to an hour
+func (item OutboxItem) nextAttempt() time.Time {
+	backoff := maxOutboxBackoff
+	if item.Attempts < 1 {
+		backoff = time.Minute
+	} else if item.Attempts < 8 {
+		backoff = time.Minute << (item.Attempts - 1)
+	}
+	if backoff > maxOutboxBackoff {
+		backoff = maxOutboxBackoff
+	}
+	return item.LastAttempt.Add(backoff)
+}
+
+// SetOutboxTTL sets how long undelivered results are kept
+func (e *Executor) SetOutboxTTL(ttl time.Duration) {
+	e.outboxTTL = ttl
+}
+
+func (e *Executor) outboxDir() string {
+	return filepath.Join(e.stateDir, "outbox")
+}
+
+// enqueue stores a result whose delivery failed so it can be delivered later
+func (e *Executor) enqueue(t *task.Task, result QueryResult, resultFilePath string, run *RunRecord, deliveryErr error) (string, error) {
+	if e.stateDir == "" {
+		return "", fmt.Errorf("no state directory configured")
+	}
+
+	now := time.Now()
+	item := OutboxItem{
+		ID:          t.Name + "_" + run.ID,
+		Task:        t.Name,
+		Destination: t.DestinationName,
+		RunID:       run.ID,
+		File:        filepath.Base(resultFilePath),
+		CreatedAt:   now,
+		LastAttempt: now,
+		Attempts:    1,
+		LastError:   deliveryErr.Error(),
+	}
+
+	if err := writeResultBundle(filepath.Join(e.outboxDir(), item.ID), t, result, resultFilePath); err != nil {
+		return "", err
+	}
+
+	// The item file is written last, so incomplete items are never picked up
+	if err := e.saveOutboxItem(item); err != nil {
+		return "", err
+	}
+	return item.ID, nil
+}
+
+func (e *Executor) saveOutboxItem(item OutboxItem) error {
+	data, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox item: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(e.outboxDir(), item.ID, outboxItemFile), data, 0600); err != nil {
+		return fmt.Errorf("failed to write outbox item: %w", err)
+	}
+	return nil
+}
+
+// ListOutbox returns the undelivered results, oldest first
+func (e *Executor) ListOutbox() ([]OutboxItem, error) {
+	entries, err := os.ReadDir(e.outboxDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	var items []OutboxItem
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(e.outboxDir(), entry.Name(), outboxItemFile))
+		if err != nil {
+			continue // still being written or broken
+		}
+		var item OutboxItem
+		if err := json.Unmarshal(data, &item); err != nil {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+	return items, nil
+}
+
+// claimOutboxItem locks an item against other goractor processes and reloads
+// it. ok is false if the item is gone or another process is delivering it.
+func (e *Executor) claimOutboxItem(id string) (item OutboxItem, release func(), ok bool, err error) {
+	path := filepath.Join(e.outboxDir(), filepath.Base(id), outboxItemFile)
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return OutboxItem{}, nil, false, nil
+		}
+		return OutboxItem{}, nil, false, fmt.Errorf("failed to open outbox item: %w", err)
+	}
+
+	locked, err := lockFile(f)
+	if err != nil || !locked {
+		f.Close()
+		return OutboxItem{}, nil, false, err
+	}
+
+	// The holder of the previous lock may have delivered and removed it
+	data, err := os.ReadFile(path)
+	if err != nil {
+		f.Close()
+		if os.IsNotExist(err) {
+			return OutboxItem{}, nil, false, nil
+		}
+		return OutboxItem{}, nil, false, fmt.Errorf("failed to read outbox item: %w", err)
+	}
+	if err := json.Unmarshal(data, &item); err != nil {
+		f.Close()
+		return OutboxItem{}, nil, false, fmt.Errorf("failed to parse outbox item: %w", err)
+	}
+	return item, func() { f.Close() }, true, nil
+}
+
+// DropOutboxItem removes an undelivered result
+func (e *Executor) DropOutboxItem(id string) error {
+	e.outboxMu.Lock()
+	defer e.outboxMu.Unlock()
+
+	if _, err := os.Stat(filepath.Join(e.outboxDir(), filepath.Base(id), outboxItemFile)); err != nil {
+		return fmt.Errorf("outbox item %s not found", id)
+	}
+	_, release, ok, err := e.claimOutboxItem(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("outbox item %s is being delivered by another process", id)
+	}
+	defer release()
+
+	return e.removeOutboxItem(id)
+}
+
+func (e *Executor) removeOutboxItem(id string) error {
+	if err := os.RemoveAll(filepath.Join(e.outboxDir(), filepath.Base(id))); err != nil {
+		return fmt.Errorf("failed to remove outbox item: %w", err)
+	}
+	return nil
+}
+
+// RetryOutbox redelivers queued results. An empty taskName retries every task;
+// unless force is set, items are only retried once their backoff has passed.
+// Items older than the outbox TTL are removed instead.
+func (e *Executor) RetryOutbox(ctx context.Context, taskName string, force bool) error {
+	e.outboxMu.Lock()
+	defer e.outboxMu.Unlock()
+
+	items, err := e.ListOutbox()
+	if err != nil {
+		return err
+	}
+
+	ttl := e.outboxTTL
+	if ttl <= 0 {
+		ttl = defaultOutboxTTL
+	}
+
+	now := time.Now()
+	for _, listed := range items {
+		if taskName != "" && listed.Task != taskName {
+			continue
+		}
+		if err := e.retryOutboxItem(ctx, listed.ID, ttl, force, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retryOutboxItem claims, expires or redelivers one item. Items claimed by
+// another process are skipped. Only errors with the outbox itself are returned.
+func (e *Executor) retryOutboxItem(ctx context.Context, id string, ttl time.Duration, force bool, now time.Time) error {
+	item, release, ok, err := e.claimOutboxItem(id)
+	if err != nil || !ok {
+		return err
+	}
+	defer release()
+
+	if now.Sub(item.CreatedAt) > ttl {
+		fmt.Printf("Outbox: giving up on delivery %s (last error: %s)\n", item.ID, item.LastError)
+		return e.expire(item)
+	}
+	if !force && now.Before(item.nextAttempt()) {
+		return nil
+	}
+
+	if err := e.redeliver(ctx, item); err != nil {
+		fmt.Printf("Outbox: delivery %s failed again: %v\n", item.ID, err)
+		return nil
+	}
+	fmt.Printf("Outbox: delivered %s to %s\n", item.ID, item.Destination)
+	return nil
+}
+
+// retryPending redelivers queued results of a task that are due. It runs after
+// the task's own delivery with a separate deadline, so a hanging destination
+// cannot take time from the query.
+func (e *Executor) retryPending(t *task.Task) {
+	ctx, cancel := context.WithTimeout(context.Background(), outboxRetryTimeout)
+	defer cancel()
+
+	if err := e.RetryOutbox(ctx, t.Name, false); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to process outbox: %v\n", err)
+	}
+}
+
+// RetryOutboxItem redelivers a single queued result immediately
+func (e *Executor) RetryOutboxItem(ctx context.Context, id string) error {
+	e.outboxMu.Lock()
+	defer e.outboxMu.Unlock()
+
+	if _, err := os.Stat(filepath.Join(e.outboxDir(), filepath.Base(id), outboxItemFile)); err != nil {
+		return fmt.Errorf("outbox item %s not found", id)
+	}
+	item, release, ok, err := e.claimOutboxItem(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("outbox item %s is being delivered by another process", id)
+	}
+	defer release()
+
+	return e.redeliver(ctx, item)
+}
+
+// redeliver sends a claimed result to its destination. On success the item is
+// removed and the original run record is marked as redelivered.
+func (e *Executor) redeliver(ctx context.Context, item OutboxItem) error {
+	dir := filepath.Join(e.outboxDir(), item.ID)
+
+	t, result, err := readResultBundle(dir)
+	if err != nil {
+		return err
+	}
+
+	dest, exists := e.destinationManager.Get(item.Destination)
+	if !exists {
+		return fmt.Errorf("destination %s not found", item.Destination)
+	}
+
+	run := &RunRecord{ID: item.RunID, Task: item.Task, Destination: item.Destination}
+	deliveryErr := e.deliver(ctx, &t, dest, result, filepath.Join(dir, item.File), run)
+	if deliveryErr != nil {
+		item.Attempts++
+		item.LastAttempt = time.Now()
+		item.LastError = deliveryErr.Error()
+		if err := e.saveOutboxItem(item); err != nil {
+			return err
+		}
+		return deliveryErr
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove delivered outbox item: %w", err)
+	}
+
+	// Keep the history in line with what actually happened
+	if original, err := e.GetRun(item.Task, item.RunID); err == nil {
+		original.Status = "redelivered"
+		original.Error = ""
+		original.Outbox = ""
+		original.Response = run.Response
+		original.ReceiptID = run.ReceiptID
+		original.Warnings = run.Warnings
+		if err := e.saveRun(&original); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to update run record: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// expire removes an item that could not be delivered within the TTL. With
+// artifact retention enabled it is kept as the run's stored result, so it can
+// still be sent with "task resend".
+func (e *Executor) expire(item OutboxItem) error {
+	artifactDir := e.artifactDir(item.Task, item.RunID)
+	if _, err := os.Stat(artifactDir); e.keepArtifacts <= 0 || err == nil {
+		return e.removeOutboxItem(item.ID)
+	}
+
+	dir := filepath.Join(e.outboxDir(), item.ID)
+	if err := os.Remove(filepath.Join(dir, outboxItemFile)); err != nil {
+		return fmt.Errorf("failed to remove outbox item: %w", err)
+	}
+	if err := os.MkdirAll(e.runsDir(item.Task), 0700); err != nil {
+		return fmt.Errorf("failed to create runs directory: %w", err)
+	}
+	if err := os.Rename(dir, artifactDir); err != nil {
+		return fmt.Errorf("failed to keep undelivered result: %w", err)
+	}
+
+	if run, err := e.GetRun(item.Task, item.RunID); err == nil {
+		run.Outbox = ""
+		run.Artifact = item.File
+		if err := e.saveRun(&run); err != nil {
+			return err
+		}
+	}
+	return e.pruneArtifacts(item.Task)
+}
+
+// decodeResult reads a stored query result, restoring the Go types that
+// convertValue produces for each column type
+func decodeResult(data []byte) (QueryResult, error) {
+	var result QueryResult
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&result); err != nil {
+		return QueryResult{}, fmt.Errorf("failed to parse stored result: %w", err)
+	}
+
+	for _, row := range result.Data {
+		for _, col := range result.Columns {
+			if v, ok := row[col.Name]; ok {
+				row[col.Name] = restoreValue(v, col.Type)
+			}
+		}
+	}
+	return result, nil
+}
+
+func restoreValue(v interface{}, columnType string) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	switch columnType {
+	case "DATE", "TIME", "TIMETZ", "TIMESTAMP", "TIMESTAMPTZ":
+		if s, ok := v.(string); ok {
+			if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				return t
+			}
+		}
+	case "BYTEA":
+		if s, ok := v.(string); ok {
+			if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+				return b
+			}
+		}
+	case "JSON", "JSONB":
+		if raw, err := json.Marshal(v); err == nil {
+			return json.RawMessage(raw)
+		}
+	}
+	return v
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package executor
+
+import "os"
+
+// lockFile is a no-op where flock is unavailable; items are then only
+// protected against concurrent delivery within one process
+func lockFile(f *os.File) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,237 @@
+package executor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/task"
+	"github.com/lib/pq"
+)
+
+const postgresStagingTable = "goractor_staging"
+
+// sendToPostgres copies the result into a table of another configured database
+func (e *Executor) sendToPostgres(ctx context.Context, t *task.Task, dest destination.Destination, result QueryResult) error {
+	opts := dest.Postgres
+	if opts.Table == "" {
+		return fmt.Errorf("postgres destination requires a table")
+	}
+	schema := opts.Schema
+	if schema == "" {
+		schema = "public"
+	}
+	mode := opts.Mode
+	if mode == "" {
+		mode = "append"
+	}
+	if mode == "upsert" && len(opts.KeyColumns) == 0 {
+		return fmt.Errorf("upsert mode requires key columns")
+	}
+
+	headers := resultHeaders(result, t.Columns)
+	target := pq.QuoteIdentifier(schema) + "." + pq.QuoteIdentifier(opts.Table)
+
+	db, err := e.openDatabase(opts.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if opts.CreateTable {
+		if _, err := tx.ExecContext(ctx, createTableStatement(target, headers, result, opts.KeyColumns)); err != nil {
+			return fmt.Errorf("failed to create table %s: %w", target, err)
+		}
+	}
+
+	switch mode {
+	case "append":
+		if err := copyRows(ctx, tx, pq.CopyInSchema(schema, opts.Table, headers...), headers, result); err != nil {
+			return err
+		}
+
+	case "truncate":
+		if _, err := tx.ExecContext(ctx, "TRUNCATE "+target); err != nil {
+			return fmt.Errorf("failed to truncate %s: %w", target, err)
+		}
+		if err := copyRows(ctx, tx, pq.CopyInSchema(schema, opts.Table, headers...), headers, result); err != nil {
+			return err
+		}
+
+	case "upsert":
+		// COPY does not support ON CONFLICT, so rows go through a staging table
+		staging := pq.QuoteIdentifier(postgresStagingTable)
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+			"CREATE TEMP TABLE %s (LIKE %s INCLUDING DEFAULTS) ON COMMIT DROP", staging, target)); err != nil {
+			return fmt.Errorf("failed to create staging table: %w", err)
+		}
+		if err := copyRows(ctx, tx, pq.CopyIn(postgresStagingTable, headers...), headers, result); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, upsertStatement(target, staging, headers, opts.KeyColumns)); err != nil {
+			return fmt.Errorf("failed to upsert into %s: %w", target, err)
+		}
+
+	default:
+		return fmt.Errorf("unsupported postgres write mode: %s", mode)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	fmt.Printf("Wrote %d rows to %s.%s (%s)\n", result.RowCount, opts.Database, target, mode)
+	return nil
+}
+
+// copyRows streams the result rows with COPY FROM STDIN
+func copyRows(ctx context.Context, tx *sql.Tx, copyStatement string, headers []string, result QueryResult) error {
+	stmt, err := tx.PrepareContext(ctx, copyStatement)
+	if err != nil {
+		return fmt.Errorf("failed to start COPY: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, row := range result.Data {
+		values := make([]interface{}, len(headers))
+		for i, header := range headers {
+			values[i] = copyValue(row[header])
+		}
+		if _, err := stmt.ExecContext(ctx, values...); err != nil {
+			return fmt.Errorf("failed to copy row: %w", err)
+		}
+	}
+
+	// Flush the buffered rows
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("failed to copy rows: %w", err)
+	}
+	return nil
+}
+
+// copyValue converts a value from convertValue into one lib/pq can send
+func copyValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case json.Number:
+		return string(val)
+	case json.RawMessage:
+		return string(val) // []byte would be sent as bytea
+	case []interface{}:
+		return arrayLiteral(val)
+	default:
+		return val
+	}
+}
+
+// arrayLiteral renders a parsed array back into PostgreSQL array syntax
+func arrayLiteral(arr []interface{}) string {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, v := range arr {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		switch val := v.(type) {
+		case nil:
+			sb.WriteString("NULL")
+		case []interface{}:
+			sb.WriteString(arrayLiteral(val))
+		case json.Number:
+			sb.WriteString(string(val))
+		case bool:
+			if val {
+				sb.WriteString("t")
+			} else {
+				sb.WriteString("f")
+			}
+		default:
+			s := fmt.Sprintf("%v", val)
+			s = strings.ReplaceAll(s, `\`, `\\`)
+			s = strings.ReplaceAll(s, `"`, `\"`)
+			sb.WriteString(`"` + s + `"`)
+		}
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+func createTableStatement(target string, headers []string, result QueryResult, keyColumns []string) string {
+	defs := make([]string, 0, len(headers)+1)
+	for _, header := range headers {
+		defs = append(defs, pq.QuoteIdentifier(header)+" "+postgresColumnType(result.ColumnType(header)))
+	}
+	if len(keyColumns) > 0 {
+		defs = append(defs, "PRIMARY KEY ("+quoteIdentifiers(keyColumns)+")")
+	}
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", target, strings.Join(defs, ", "))
+}
+
+func upsertStatement(target, staging string, headers, keyColumns []string) string {
+	keys := make(map[string]bool, len(keyColumns))
+	for _, key := range keyColumns {
+		keys[key] = true
+	}
+
+	var updates []string
+	for _, header := range headers {
+		if !keys[header] {
+			col := pq.QuoteIdentifier(header)
+			updates = append(updates, col+" = EXCLUDED."+col)
+		}
+	}
+
+	action := "DO NOTHING"
+	if len(updates) > 0 {
+		action = "DO UPDATE SET " + strings.Join(updates, ", ")
+	}
+
+	columns := quoteIdentifiers(headers)
+	return fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s ON CONFLICT (%s) %s",
+		target, columns, columns, staging, quoteIdentifiers(keyColumns), action)
+}
+
+func quoteIdentifiers(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = pq.QuoteIdentifier(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// postgresColumnType maps a result column type to a column definition type.
+// Unknown types fall back to text.
+func postgresColumnType(columnType string) string {
+	if strings.HasPrefix(columnType, "_") {
+		return postgresColumnType(strings.TrimPrefix(columnType, "_")) + "[]"
+	}
+	switch columnType {
+	case "INT2":
+		return "smallint"
+	case "INT4":
+		return "integer"
+	case "INT8":
+		return "bigint"
+	case "FLOAT4":
+		return "real"
+	case "FLOAT8":
+		return "double precision"
+	case "NUMERIC", "DECIMAL":
+		return "numeric"
+	case "BOOL":
+		return "boolean"
+	case "DATE", "TIME", "TIMETZ", "TIMESTAMP", "TIMESTAMPTZ", "INTERVAL",
+		"JSON", "JSONB", "BYTEA", "UUID", "INET", "CIDR", "MACADDR":
+		return strings.ToLower(columnType)
+	default:
+		return "text"
+	}
+}
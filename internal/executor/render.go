@@ -0,0 +1,261 @@
+package executor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+const (
+	defaultTimestampLayout = "2006-01-02 15:04:05"
+	defaultDateLayout      = "2006-01-02"
+	defaultTimeLayout      = "15:04:05"
+)
+
+// convertValue turns a value scanned by lib/pq into a structured Go value
+// based on the PostgreSQL column type. Types the driver leaves as raw bytes
+// become json.Number (numeric), json.RawMessage (json/jsonb), []interface{}
+// (arrays) or string; bytea stays []byte.
+func convertValue(v interface{}, columnType string) interface{} {
+	b, ok := v.([]byte)
+	if !ok {
+		return v
+	}
+
+	switch {
+	case columnType == "BYTEA":
+		return b
+	case isNumericType(columnType):
+		return json.Number(string(b))
+	case columnType == "JSON" || columnType == "JSONB":
+		return json.RawMessage(b)
+	case strings.HasPrefix(columnType, "_"):
+		if arr, err := parseArray(string(b), strings.TrimPrefix(columnType, "_")); err == nil {
+			return arr
+		}
+	}
+	return string(b)
+}
+
+func isNumericType(columnType string) bool {
+	switch columnType {
+	case "NUMERIC", "DECIMAL", "INT2", "INT4", "INT8", "FLOAT4", "FLOAT8":
+		return true
+	}
+	return false
+}
+
+// parseArray parses a PostgreSQL array literal such as {1,2,NULL} or
+// {{"a","b"},{"c","d"}} into nested slices
+func parseArray(s, elemType string) ([]interface{}, error) {
+	arr, rest, err := parseArrayLevel(s, elemType)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, fmt.Errorf("unexpected trailing data in array: %q", rest)
+	}
+	return arr, nil
+}
+
+func parseArrayLevel(s, elemType string) ([]interface{}, string, error) {
+	if !strings.HasPrefix(s, "{") {
+		return nil, s, fmt.Errorf("array must start with '{'")
+	}
+	s = s[1:]
+
+	arr := []interface{}{}
+	if strings.HasPrefix(s, "}") {
+		return arr, s[1:], nil
+	}
+
+	for {
+		var elem interface{}
+		switch {
+		case strings.HasPrefix(s, "{"):
+			nested, rest, err := parseArrayLevel(s, elemType)
+			if err != nil {
+				return nil, s, err
+			}
+			elem, s = nested, rest
+
+		case strings.HasPrefix(s, `"`):
+			var sb strings.Builder
+			i := 1
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				sb.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, s, fmt.Errorf("unterminated quoted array element")
+			}
+			elem, s = sb.String(), s[i+1:]
+
+		default:
+			end := strings.IndexAny(s, ",}")
+			if end < 0 {
+				return nil, s, fmt.Errorf("unterminated array")
+			}
+			raw := s[:end]
+			s = s[end:]
+			if raw == "NULL" {
+				elem = nil
+			} else {
+				elem = convertArrayElement(raw, elemType)
+			}
+		}
+		arr = append(arr, elem)
+
+		if strings.HasPrefix(s, ",") {
+			s = s[1:]
+			continue
+		}
+		if strings.HasPrefix(s, "}") {
+			return arr, s[1:], nil
+		}
+		return nil, s, fmt.Errorf("malformed array")
+	}
+}
+
+func convertArrayElement(raw, elemType string) interface{} {
+	switch {
+	case isNumericType(elemType):
+		return json.Number(raw)
+	case elemType == "BOOL":
+		return raw == "t"
+	}
+	return raw
+}
+
+// valueFormatter renders values for text outputs (CSV, messages) and JSON
+// according to the task's format options
+type valueFormatter struct {
+	loc             *time.Location
+	timestampLayout string
+	dateLayout      string
+	precision       *int
+}
+
+func newValueFormatter(t *task.Task) valueFormatter {
+	loc, err := time.LoadLocation(t.Timezone)
+	if err != nil {
+		loc = time.Local
+	}
+
+	f := valueFormatter{
+		loc:             loc,
+		timestampLayout: defaultTimestampLayout,
+		dateLayout:      defaultDateLayout,
+		precision:       t.Format.NumericPrecision,
+	}
+	if t.Format.TimestampLayout != "" {
+		f.timestampLayout = t.Format.TimestampLayout
+	}
+	if t.Format.DateLayout != "" {
+		f.dateLayout = t.Format.DateLayout
+	}
+	return f
+}
+
+// Text renders a value as a string. NULL values render as an empty string.
+func (f valueFormatter) Text(v interface{}, columnType string) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return f.formatTime(val, columnType)
+	case bool:
+		return strconv.FormatBool(val)
+	case json.Number:
+		return f.formatNumber(string(val), columnType)
+	case float64:
+		return f.formatNumber(strconv.FormatFloat(val, 'f', -1, 64), columnType)
+	case json.RawMessage:
+		return string(val)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(val)
+	case []interface{}:
+		data, err := json.Marshal(f.jsonArray(val, columnType))
+		if err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// JSON returns a value suitable for encoding/json, keeping JSON documents
+// and arrays structured
+func (f valueFormatter) JSON(v interface{}, columnType string) interface{} {
+	switch val := v.(type) {
+	case time.Time:
+		return f.formatTime(val, columnType)
+	case json.Number:
+		n := f.formatNumber(string(val), columnType)
+		if strings.ContainsAny(n, "NnIi") { // NaN and Infinity are not valid JSON numbers
+			return n
+		}
+		return json.Number(n)
+	case float64:
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			return nil
+		}
+		return json.Number(f.formatNumber(strconv.FormatFloat(val, 'f', -1, 64), columnType))
+	case []byte:
+		return base64.StdEncoding.EncodeToString(val)
+	case []interface{}:
+		return f.jsonArray(val, columnType)
+	default:
+		return val
+	}
+}
+
+func (f valueFormatter) jsonArray(arr []interface{}, columnType string) []interface{} {
+	elemType := strings.TrimPrefix(columnType, "_")
+	out := make([]interface{}, len(arr))
+	for i, v := range arr {
+		out[i] = f.JSON(v, elemType)
+	}
+	return out
+}
+
+func (f valueFormatter) formatTime(t time.Time, columnType string) string {
+	switch columnType {
+	case "DATE":
+		return t.Format(f.dateLayout)
+	case "TIME", "TIMETZ":
+		return t.Format(defaultTimeLayout)
+	case "TIMESTAMPTZ":
+		return t.In(f.loc).Format(f.timestampLayout)
+	default:
+		// timestamp without time zone is rendered as stored
+		return t.Format(f.timestampLayout)
+	}
+}
+
+// formatNumber rounds non-integer numbers to the configured precision
+func (f valueFormatter) formatNumber(s, columnType string) string {
+	if f.precision == nil {
+		return s
+	}
+	switch columnType {
+	case "NUMERIC", "DECIMAL", "FLOAT4", "FLOAT8":
+	default:
+		return s
+	}
+	n, ok := new(big.Float).SetPrec(256).SetString(s)
+	if !ok { // NaN, Infinity
+		return s
+	}
+	return n.Text('f', *f.precision)
+}
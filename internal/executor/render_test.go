@@ -0,0 +1,61 @@
+package executor
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseArray(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		elemType string
+		want     []interface{}
+		wantErr  bool
+	}{
+		{name: "empty", input: "{}", elemType: "INT4", want: []interface{}{}},
+		{name: "numbers", input: "{1,2,3}", elemType: "INT4", want: []interface{}{json.Number("1"), json.Number("2"), json.Number("3")}},
+		{name: "null element", input: "{1,NULL,3}", elemType: "INT8", want: []interface{}{json.Number("1"), nil, json.Number("3")}},
+		{name: "booleans", input: "{t,f}", elemType: "BOOL", want: []interface{}{true, false}},
+		{name: "unquoted text", input: "{a,b}", elemType: "TEXT", want: []interface{}{"a", "b"}},
+		{name: "quoted text", input: `{"a,b","c}d"}`, elemType: "TEXT", want: []interface{}{"a,b", "c}d"}},
+		{name: "escaped quote and backslash", input: `{"say \"hi\"","back\\slash"}`, elemType: "TEXT", want: []interface{}{`say "hi"`, `back\slash`}},
+		{name: "quoted NULL is a string", input: `{"NULL"}`, elemType: "TEXT", want: []interface{}{"NULL"}},
+		{name: "nested", input: `{{1,2},{3,4}}`, elemType: "INT4", want: []interface{}{
+			[]interface{}{json.Number("1"), json.Number("2")},
+			[]interface{}{json.Number("3"), json.Number("4")},
+		}},
+		{name: "nested empty", input: "{{},{}}", elemType: "TEXT", want: []interface{}{[]interface{}{}, []interface{}{}}},
+		{name: "missing brace", input: "1,2}", elemType: "INT4", wantErr: true},
+		{name: "unterminated", input: "{1,2", elemType: "INT4", wantErr: true},
+		{name: "unterminated quote", input: `{"abc}`, elemType: "TEXT", wantErr: true},
+		{name: "trailing data", input: "{1}x", elemType: "INT4", wantErr: true},
+		{name: "malformed after quote", input: `{"a"b}`, elemType: "TEXT", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseArray(tt.input, tt.elemType)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseArray(%q) = %#v, want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseArray(%q) error: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseArray(%q) = %#v, want %#v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertValueArrayFallback(t *testing.T) {
+	// Unparseable arrays are kept as text rather than dropped
+	if got := convertValue([]byte("{1,2"), "_INT4"); got != "{1,2" {
+		t.Errorf("convertValue = %#v, want the raw string", got)
+	}
+}
@@ -0,0 +1,118 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxReceiptBody limits the response body read for receipt extraction
+	maxReceiptBody = 1 << 20
+	// maxErrorSnippet limits the response body quoted in error messages
+	maxErrorSnippet = 512
+)
+
+// captureResponse keeps the status, headers and a truncated body of a response
+func captureResponse(resp *http.Response, body []byte) *HTTPResponse {
+	captured := &HTTPResponse{
+		StatusCode: resp.StatusCode,
+		Headers:    make(map[string]string, len(resp.Header)),
+	}
+	for key, values := range resp.Header {
+		captured.Headers[key] = strings.Join(values, ", ")
+	}
+
+	if len(body) > maxResponseCapture {
+		body = body[:maxResponseCapture]
+		captured.Truncated = true
+	}
+	captured.Body = strings.ToValidUTF8(string(body), "")
+	return captured
+}
+
+// responseSnippet returns the start of a response body for error messages
+func responseSnippet(body []byte) string {
+	s := strings.TrimSpace(strings.ToValidUTF8(string(body), ""))
+	if len(s) <= maxErrorSnippet {
+		return s
+	}
+	s = s[:maxErrorSnippet]
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s + "..."
+}
+
+// extractJSONPath returns the value at a simple JSON path such as
+// $.data.id or items[0].receipt. Non-string values are returned as JSON.
+func extractJSONPath(body []byte, path string) (string, error) {
+	var doc interface{}
+	decoder := json.NewDecoder(strings.NewReader(string(body)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return "", fmt.Errorf("response is not JSON: %w", err)
+	}
+
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	current := doc
+	for _, segment := range splitJSONPath(path) {
+		if index, err := strconv.Atoi(segment); err == nil {
+			arr, ok := current.([]interface{})
+			if !ok || index < 0 || index >= len(arr) {
+				return "", fmt.Errorf("path %s not found in response", path)
+			}
+			current = arr[index]
+			continue
+		}
+
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("path %s not found in response", path)
+		}
+		if current, ok = obj[segment]; !ok {
+			return "", fmt.Errorf("path %s not found in response", path)
+		}
+	}
+
+	switch val := current.(type) {
+	case string:
+		return val, nil
+	case nil:
+		return "", fmt.Errorf("path %s is null in response", path)
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
+
+// splitJSONPath splits "items[0].id" into "items", "0", "id"
+func splitJSONPath(path string) []string {
+	var segments []string
+	for _, part := range strings.Split(path, ".") {
+		for part != "" {
+			open := strings.Index(part, "[")
+			if open < 0 {
+				segments = append(segments, part)
+				break
+			}
+			if open > 0 {
+				segments = append(segments, part[:open])
+			}
+			end := strings.Index(part[open:], "]")
+			if end < 0 {
+				segments = append(segments, part[open+1:])
+				break
+			}
+			segments = append(segments, part[open+1:open+end])
+			part = part[open+end+1:]
+		}
+	}
+	return segments
+}
@@ -0,0 +1,78 @@
+package executor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractJSONPath(t *testing.T) {
+	body := []byte(`{
+		"id": "r-1",
+		"data": {"receipt": {"id": 12345678901234567890, "ok": true}},
+		"items": [{"id": "a"}, {"id": "b", "tags": ["x", "y"]}],
+		"empty": null
+	}`)
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{name: "top level", path: "$.id", want: "r-1"},
+		{name: "without $ prefix", path: "id", want: "r-1"},
+		{name: "nested", path: "$.data.receipt.ok", want: "true"},
+		{name: "large number keeps precision", path: "$.data.receipt.id", want: "12345678901234567890"},
+		{name: "object as JSON", path: "$.items[0]", want: `{"id":"a"}`},
+		{name: "array index", path: "$.items[1].id", want: "b"},
+		{name: "consecutive indexes", path: "$.items[1].tags[1]", want: "y"},
+		{name: "index out of range", path: "$.items[2].id", wantErr: true},
+		{name: "negative index", path: "$.items[-1]", wantErr: true},
+		{name: "index into object", path: "$.data[0]", wantErr: true},
+		{name: "missing key", path: "$.data.missing", wantErr: true},
+		{name: "key into scalar", path: "$.id.value", wantErr: true},
+		{name: "null value", path: "$.empty", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractJSONPath(body, tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("extractJSONPath(%q) = %q, want error", tt.path, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("extractJSONPath(%q) error: %v", tt.path, err)
+			}
+			if got != tt.want {
+				t.Errorf("extractJSONPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractJSONPathNotJSON(t *testing.T) {
+	if _, err := extractJSONPath([]byte("<html>accepted</html>"), "$.id"); err == nil {
+		t.Error("extractJSONPath succeeded on a non-JSON body")
+	}
+}
+
+func TestSplitJSONPath(t *testing.T) {
+	tests := map[string][]string{
+		"id":          {"id"},
+		"data.id":     {"data", "id"},
+		"items[0].id": {"items", "0", "id"},
+		"items[0][1]": {"items", "0", "1"},
+		"[2].id":      {"2", "id"},
+		"items[0":     {"items", "0"},
+		"":            nil,
+		"a..b":        {"a", "b"},
+	}
+	for path, want := range tests {
+		if got := splitJSONPath(path); !reflect.DeepEqual(got, want) {
+			t.Errorf("splitJSONPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
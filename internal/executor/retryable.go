@@ -0,0 +1,74 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// retryableError marks a delivery error that may succeed when tried again
+// later, such as a 5xx response
+type retryableError struct {
+	err error
+}
+
+func (e retryableError) Error() string { return e.err.Error() }
+func (e retryableError) Unwrap() error { return e.err }
+
+// retryable marks err as worth retrying
+func retryable(err error) error {
+	return retryableError{err: err}
+}
+
+// retryableStatus reports whether an HTTP status means the server may accept
+// the same request later
+func retryableStatus(code int) bool {
+	return code >= 500 || code == http.StatusTooManyRequests || code == http.StatusRequestTimeout
+}
+
+// isRetryable reports whether a failed delivery should be kept in the outbox.
+// Configuration errors and rejected requests are not retried.
+func isRetryable(err error) bool {
+	var marked retryableError
+	if errors.As(err, &marked) {
+		return true
+	}
+
+	// Timeouts, dropped connections and unreachable hosts
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	// Client libraries that classify their own errors (slack, kafka)
+	var retryer interface{ Retryable() bool }
+	if errors.As(err, &retryer) {
+		return retryer.Retryable()
+	}
+	var temporary interface{ Temporary() bool }
+	if errors.As(err, &temporary) {
+		return temporary.Temporary()
+	}
+
+	// Lost connections, serialization failures and an overloaded or restarting server
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		code := string(pqErr.Code)
+		return strings.HasPrefix(code, "08") || strings.HasPrefix(code, "40") ||
+			strings.HasPrefix(code, "53") || strings.HasPrefix(code, "57P")
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+const (
+	// maxRunRecords is the number of run records kept per task
+	maxRunRecords = 200
+	// maxResponseCapture limits the response body kept in a run record
+	maxResponseCapture = 4096
+)
+
+// RunRecord describes one execution of a task, stored as
+// <stateDir>/runs/<task>/<id>.json
+type RunRecord struct {
+	ID          string        `json:"id" yaml:"id"`
+	Task        string        `json:"task" yaml:"task"`
+	Destination string        `json:"destination" yaml:"destination"`
+	StartedAt   time.Time     `json:"started_at" yaml:"started_at"`
+	FinishedAt  time.Time     `json:"finished_at" yaml:"finished_at"`
+	Status      string        `json:"status" yaml:"status"` // success, failed or redelivered
+	Error       string        `json:"error,omitempty" yaml:"error,omitempty"`
+	RowCount    int           `json:"row_count" yaml:"row_count"`
+	Response    *HTTPResponse `json:"response,omitempty" yaml:"response,omitempty"`
+	ReceiptID   string        `json:"receipt_id,omitempty" yaml:"receipt_id,omitempty"`
+	Warnings    []string      `json:"warnings,omitempty" yaml:"warnings,omitempty"` // problems that did not fail the run
+	Outbox      string        `json:"outbox,omitempty" yaml:"outbox,omitempty"`     // outbox item holding the undelivered result
+	Artifact    string        `json:"artifact,omitempty" yaml:"artifact,omitempty"` // stored result file, see "task resend"
+	ResentFrom  string        `json:"resent_from,omitempty" yaml:"resent_from,omitempty"`
+}
+
+// HTTPResponse is the captured response of an HTTP destination
+type HTTPResponse struct {
+	StatusCode int               `json:"status_code" yaml:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body       string            `json:"body,omitempty" yaml:"body,omitempty"`
+	Truncated  bool              `json:"truncated,omitempty" yaml:"truncated,omitempty"`
+}
+
+func newRunRecord(t *task.Task) *RunRecord {
+	now := time.Now()
+	return &RunRecord{
+		ID:          fmt.Sprintf("%s_%03d", now.Format("20060102_150405"), now.Nanosecond()/int(time.Millisecond)),
+		Task:        t.Name,
+		Destination: t.DestinationName,
+		StartedAt:   now,
+	}
+}
+
+// warn records a problem that does not fail the run
+func (run *RunRecord) warn(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	fmt.Fprintf(os.Stderr, "warning: %s\n", msg)
+	run.Warnings = append(run.Warnings, msg)
+}
+
+func (e *Executor) runsDir(taskName string) string {
+	return filepath.Join(e.stateDir, "runs", taskName)
+}
+
+// finishRun stores the run record. Failing to store it does not fail the run.
+func (e *Executor) finishRun(run *RunRecord, runErr error) {
+	run.FinishedAt = time.Now()
+	run.Status = "success"
+	if runErr != nil {
+		run.Status = "failed"
+		run.Error = runErr.Error()
+	}
+
+	if err := e.saveRun(run); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to save run record: %v\n", err)
+	}
+}
+
+func (e *Executor) saveRun(run *RunRecord) error {
+	if e.stateDir == "" {
+		return nil
+	}
+
+	dir := e.runsDir(run.Task)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create runs directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run record: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, run.ID+".json"), data, 0600); err != nil {
+		return fmt.Errorf("failed to write run record: %w", err)
+	}
+
+	return e.pruneRuns(run.Task)
+}
+
+// pruneRuns removes the oldest run records beyond maxRunRecords
+func (e *Executor) pruneRuns(taskName string) error {
+	ids, err := e.runIDs(taskName)
+	if err != nil {
+		return err
+	}
+	for len(ids) > maxRunRecords {
+		if err := os.Remove(filepath.Join(e.runsDir(taskName), ids[0]+".json")); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove old run record: %w", err)
+		}
+		if err := os.RemoveAll(e.artifactDir(taskName, ids[0])); err != nil {
+			return fmt.Errorf("failed to remove old result: %w", err)
+		}
+		ids = ids[1:]
+	}
+	return nil
+}
+
+// runIDs returns the stored run IDs of a task, oldest first
+func (e *Executor) runIDs(taskName string) ([]string, error) {
+	entries, err := os.ReadDir(e.runsDir(taskName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read runs directory: %w", err)
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// ListRuns returns the stored runs of a task, newest first
+func (e *Executor) ListRuns(taskName string) ([]RunRecord, error) {
+	ids, err := e.runIDs(taskName)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]RunRecord, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		run, err := e.GetRun(taskName, ids[i])
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// GetRun returns a stored run of a task
+func (e *Executor) GetRun(taskName, id string) (RunRecord, error) {
+	data, err := os.ReadFile(filepath.Join(e.runsDir(taskName), filepath.Base(id)+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return RunRecord{}, fmt.Errorf("run %s of task %s not found", id, taskName)
+		}
+		return RunRecord{}, fmt.Errorf("failed to read run record: %w", err)
+	}
+
+	var run RunRecord
+	if err := json.Unmarshal(data, &run); err != nil {
+		return RunRecord{}, fmt.Errorf("failed to parse run record: %w", err)
+	}
+	return run, nil
+}
@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/task"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const (
+	defaultS3Endpoint    = "https://s3.amazonaws.com"
+	defaultS3KeyTemplate = "{{task}}/{{date}}/{{task}}_{{timestamp}}.{{ext}}"
+)
+
+func (e *Executor) sendToS3(ctx context.Context, t *task.Task, dest destination.Destination, result QueryResult, resultFilePath string) error {
+	client, err := newS3Client(dest.S3)
+	if err != nil {
+		return err
+	}
+
+	keyTemplate := dest.S3.KeyTemplate
+	if keyTemplate == "" {
+		keyTemplate = defaultS3KeyTemplate
+	}
+	key := strings.TrimPrefix(expandTemplate(keyTemplate, t, result.Timestamp), "/")
+
+	resultFile, err := os.Open(resultFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to open result file: %w", err)
+	}
+	defer resultFile.Close()
+
+	fileStat, err := resultFile.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat result file: %w", err)
+	}
+
+	// Files larger than the part size are uploaded in parts
+	opts := minio.PutObjectOptions{ContentType: contentType(t.OutputFormat)}
+	if dest.S3.PartSizeMB > 0 {
+		opts.PartSize = uint64(dest.S3.PartSizeMB) * 1024 * 1024
+	}
+
+	if _, err := client.PutObject(ctx, dest.S3.Bucket, key, resultFile, fileStat.Size(), opts); err != nil {
+		// ToErrorResponse does not unwrap, so check the status before wrapping
+		code := minio.ToErrorResponse(err).StatusCode
+		err = fmt.Errorf("failed to upload to s3://%s/%s: %w", dest.S3.Bucket, key, err)
+		if retryableStatus(code) {
+			return retryable(err)
+		}
+		return err
+	}
+
+	fmt.Printf("Uploaded result to s3://%s/%s\n", dest.S3.Bucket, key)
+	return nil
+}
+
+func newS3Client(opts destination.S3Options) (*minio.Client, error) {
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		endpoint = defaultS3Endpoint
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint %s: %w", opts.Endpoint, err)
+	}
+
+	// Without static keys, fall back to the environment, ~/.aws/credentials and instance roles
+	creds := credentials.NewStaticV4(opts.AccessKeyID, opts.SecretAccessKey, "")
+	if opts.AccessKeyID == "" {
+		creds = credentials.NewChainCredentials([]credentials.Provider{
+			&credentials.EnvAWS{},
+			&credentials.FileAWSCredentials{},
+			&credentials.IAM{},
+		})
+	}
+
+	lookup := minio.BucketLookupAuto
+	if opts.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(u.Host, &minio.Options{
+		Creds:        creds,
+		Secure:       u.Scheme == "https",
+		Region:       opts.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 client: %w", err)
+	}
+	return client, nil
+}
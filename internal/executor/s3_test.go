@@ -0,0 +1,66 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/task"
+	"github.com/minio/minio-go/v7"
+)
+
+func TestSendToS3Errors(t *testing.T) {
+	// The client retries failed requests itself; once is enough here
+	maxRetry := minio.MaxRetry
+	minio.MaxRetry = 1
+	defer func() { minio.MaxRetry = maxRetry }()
+
+	tests := []struct {
+		name      string
+		status    int
+		code      string
+		retryable bool
+	}{
+		{name: "unavailable", status: http.StatusServiceUnavailable, code: "SlowDown", retryable: true},
+		{name: "rate limited", status: http.StatusTooManyRequests, code: "SlowDown", retryable: true},
+		{name: "access denied", status: http.StatusForbidden, code: "AccessDenied", retryable: false},
+		{name: "missing bucket", status: http.StatusNotFound, code: "NoSuchBucket", retryable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(io.Discard, r.Body)
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(tt.status)
+				fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>%s</Code><Message>stub error</Message></Error>`, tt.code)
+			}))
+			defer server.Close()
+
+			dest := destination.Destination{
+				Type: "s3",
+				S3: destination.S3Options{
+					Endpoint:        server.URL,
+					Region:          "us-east-1",
+					Bucket:          "reports",
+					PathStyle:       true,
+					AccessKeyID:     "key",
+					SecretAccessKey: "secret",
+				},
+			}
+
+			e := &Executor{}
+			err := e.sendToS3(context.Background(), &task.Task{Name: "t", OutputFormat: "csv"}, dest, testResult(1), writeResultFile(t))
+			if err == nil {
+				t.Fatal("sendToS3 succeeded, want error")
+			}
+			if isRetryable(err) != tt.retryable {
+				t.Errorf("isRetryable(%v) = %v, want %v", err, !tt.retryable, tt.retryable)
+			}
+		})
+	}
+}
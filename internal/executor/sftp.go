@@ -0,0 +1,180 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/task"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const defaultSFTPFilenameTemplate = "{{task}}_{{timestamp}}.{{ext}}"
+
+func (e *Executor) sendToSFTP(ctx context.Context, t *task.Task, dest destination.Destination, result QueryResult, resultFilePath string) error {
+	opts := dest.SFTP
+
+	sshConfig, err := sftpClientConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	port := opts.Port
+	if port == 0 {
+		port = 22
+	}
+	addr := net.JoinHostPort(opts.Host, strconv.Itoa(port))
+
+	dialer := net.Dialer{Timeout: sshConfig.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, sshConfig)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to establish ssh connection to %s: %w", addr, err)
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+	defer sshClient.Close()
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		return fmt.Errorf("failed to start sftp session: %w", err)
+	}
+	defer client.Close()
+
+	filenameTemplate := opts.FilenameTemplate
+	if filenameTemplate == "" {
+		filenameTemplate = defaultSFTPFilenameTemplate
+	}
+	filename := expandTemplate(filenameTemplate, t, result.Timestamp)
+	remotePath := path.Join(opts.RemoteDir, filename)
+	tmpPath := path.Join(path.Dir(remotePath), "."+path.Base(remotePath)+".part")
+
+	if dir := path.Dir(remotePath); dir != "." {
+		if err := client.MkdirAll(dir); err != nil {
+			return fmt.Errorf("failed to create remote directory %s: %w", dir, err)
+		}
+	}
+
+	// Upload under a temporary name and rename, so partners never pick up a partial file
+	if err := sftpUpload(client, resultFilePath, tmpPath); err != nil {
+		client.Remove(tmpPath)
+		return err
+	}
+	if err := sftpRename(client, tmpPath, remotePath); err != nil {
+		client.Remove(tmpPath)
+		return err
+	}
+
+	fmt.Printf("Uploaded result to sftp://%s%s\n", addr, remotePath)
+	return nil
+}
+
+func sftpUpload(client *sftp.Client, localPath, remotePath string) error {
+	local, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open result file: %w", err)
+	}
+	defer local.Close()
+
+	remote, err := client.Create(remotePath)
+	if err != nil {
+		return fmt.Errorf("failed to create remote file %s: %w", remotePath, err)
+	}
+
+	if _, err := io.Copy(remote, local); err != nil {
+		remote.Close()
+		return fmt.Errorf("failed to upload %s: %w", remotePath, err)
+	}
+	if err := remote.Close(); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", remotePath, err)
+	}
+	return nil
+}
+
+// sftpRename moves the uploaded file into place, replacing an existing file
+func sftpRename(client *sftp.Client, from, to string) error {
+	if _, ok := client.HasExtension("posix-rename@openssh.com"); ok {
+		if err := client.PosixRename(from, to); err != nil {
+			return fmt.Errorf("failed to rename %s to %s: %w", from, to, err)
+		}
+		return nil
+	}
+
+	// Plain SFTP rename fails if the target exists
+	if _, err := client.Stat(to); err == nil {
+		if err := client.Remove(to); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", to, err)
+		}
+	}
+	if err := client.Rename(from, to); err != nil {
+		return fmt.Errorf("failed to rename %s to %s: %w", from, to, err)
+	}
+	return nil
+}
+
+func sftpClientConfig(opts destination.SFTPOptions) (*ssh.ClientConfig, error) {
+	var auth []ssh.AuthMethod
+	if opts.PrivateKeyPath != "" {
+		key, err := os.ReadFile(expandHome(opts.PrivateKeyPath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key: %w", err)
+		}
+
+		var signer ssh.Signer
+		if opts.Passphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(opts.Passphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey(key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if opts.Password != "" {
+		auth = append(auth, ssh.Password(opts.Password))
+	}
+	if len(auth) == 0 {
+		return nil, fmt.Errorf("sftp destination requires a password or private key")
+	}
+
+	knownHostsPath := opts.KnownHostsPath
+	if knownHostsPath == "" {
+		knownHostsPath = "~/.ssh/known_hosts"
+	}
+	hostKeyCallback, err := knownhosts.New(expandHome(knownHostsPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known_hosts: %w", err)
+	}
+
+	return &ssh.ClientConfig{
+		User:            opts.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         30 * time.Second,
+	}, nil
+}
+
+// expandHome replaces a leading ~/ with the user's home directory
+func expandHome(p string) string {
+	if len(p) < 2 || p[:2] != "~/" {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, p[2:])
+}
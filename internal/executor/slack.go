@@ -0,0 +1,259 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/task"
+	"github.com/slack-go/slack"
+)
+
+const (
+	defaultSlackMaxTableRows = 20
+	// Slack limits section text to 3000 characters
+	defaultSlackMaxTableChars = 2900
+)
+
+func (e *Executor) sendToSlack(ctx context.Context, t *task.Task, dest destination.Destination, result QueryResult, resultFilePath string) error {
+	if dest.Token.Type == "webhook" {
+		if t.Slack.Threading != "" {
+			fmt.Printf("Slack threading is not supported for webhooks, posting a new message\n")
+		}
+		t, err := withMentions(ctx, nil, t, result)
+		if err != nil {
+			return err
+		}
+		return e.sendToSlackWebhook(ctx, t, dest, result)
+	}
+
+	api := slack.New(dest.Token.Value)
+	t, err := withMentions(ctx, api, t, result)
+	if err != nil {
+		return err
+	}
+	channel := strings.Replace(dest.Channel, "#", "", 1)
+
+	switch t.Slack.Threading {
+	case "daily":
+		return e.sendToSlackDailyThread(ctx, api, channel, t, dest, result, resultFilePath)
+	case "update":
+		return e.updateSlackMessage(ctx, api, channel, t, dest, result, resultFilePath)
+	}
+
+	return postSlackResult(ctx, api, channel, "", t, dest, result, resultFilePath)
+}
+
+// postSlackResult posts the result as a table or file upload, as a thread
+// reply when threadTS is set
+func postSlackResult(ctx context.Context, api *slack.Client, channel, threadTS string, t *task.Task, dest destination.Destination, result QueryResult, resultFilePath string) error {
+	if dest.Slack.Delivery == "table" {
+		if table, ok := slackTable(t, dest, result); ok {
+			_, _, err := api.PostMessageContext(ctx, channel,
+				slack.MsgOptionText(t.Message, false),
+				slack.MsgOptionBlocks(slackTableBlocks(t, result, table)...),
+				slack.MsgOptionTS(threadTS),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to post table to slack: %w", err)
+			}
+			return nil
+		}
+		// Too large for a message, fall back to a file upload
+	}
+
+	resultFile, err := os.Open(resultFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to open result file: %w", err)
+	}
+	defer resultFile.Close()
+
+	fileStat, err := resultFile.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat result file: %w", err)
+	}
+
+	params := slack.UploadFileV2Parameters{
+		Filename:        filepath.Base(resultFilePath),
+		FileSize:        int(fileStat.Size()),
+		Channel:         channel,
+		File:            resultFilePath,
+		Reader:          resultFile,
+		InitialComment:  t.Message,
+		ThreadTimestamp: threadTS,
+	}
+	if _, err := api.UploadFileV2Context(ctx, params); err != nil {
+		return fmt.Errorf("failed to upload file to slack: %w", err)
+	}
+	return nil
+}
+
+// sendToSlackDailyThread posts a parent message for the first run of the day
+// and every run's result as a reply in its thread
+func (e *Executor) sendToSlackDailyThread(ctx context.Context, api *slack.Client, channel string, t *task.Task, dest destination.Destination, result QueryResult, resultFilePath string) error {
+	today := expandTemplate("{{date}}", t, result.Timestamp)
+
+	thread, err := e.loadSlackThread(t.Name)
+	if err != nil {
+		return err
+	}
+
+	if thread == nil || thread.Mode != "daily" || thread.Date != today {
+		channelID, ts, err := api.PostMessageContext(ctx, channel,
+			slack.MsgOptionText(fmt.Sprintf("%s (%s)", t.Message, today), false))
+		if err != nil {
+			return fmt.Errorf("failed to post thread parent to slack: %w", err)
+		}
+		thread = &slackThread{Mode: "daily", Date: today, Channel: channelID, TS: ts}
+		// The parent is already posted, so a retry must not post another one
+		if err := e.saveSlackThread(t.Name, thread); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to save slack thread: %v\n", err)
+		}
+	}
+
+	return postSlackResult(ctx, api, thread.Channel, thread.TS, t, dest, result, resultFilePath)
+}
+
+// updateSlackMessage keeps a single pinned message up to date with the latest
+// result. Results too large for a message are uploaded to its thread.
+func (e *Executor) updateSlackMessage(ctx context.Context, api *slack.Client, channel string, t *task.Task, dest destination.Destination, result QueryResult, resultFilePath string) error {
+	table, fits := slackTable(t, dest, result)
+	var blocks []slack.Block
+	if fits {
+		blocks = slackTableBlocks(t, result, table)
+	} else {
+		blocks = slackSummaryBlocks(t, result)
+	}
+	options := []slack.MsgOption{
+		slack.MsgOptionText(t.Message, false),
+		slack.MsgOptionBlocks(blocks...),
+	}
+
+	thread, err := e.loadSlackThread(t.Name)
+	if err != nil {
+		return err
+	}
+
+	updated := false
+	if thread != nil && thread.Mode == "update" {
+		_, _, _, err := api.UpdateMessageContext(ctx, thread.Channel, thread.TS, options...)
+		if err == nil {
+			updated = true
+		} else if err.Error() != "message_not_found" && err.Error() != "channel_not_found" {
+			return fmt.Errorf("failed to update slack message: %w", err)
+		}
+	}
+
+	if !updated {
+		// First run, or the previous message was deleted
+		channelID, ts, err := api.PostMessageContext(ctx, channel, options...)
+		if err != nil {
+			return fmt.Errorf("failed to post message to slack: %w", err)
+		}
+		thread = &slackThread{Mode: "update", Channel: channelID, TS: ts}
+		if err := e.saveSlackThread(t.Name, thread); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to save slack thread: %v\n", err)
+		}
+		if err := api.AddPinContext(ctx, channelID, slack.NewRefToMessage(channelID, ts)); err != nil {
+			fmt.Printf("Failed to pin slack message: %v\n", err)
+		}
+	}
+
+	if !fits {
+		fileDest := dest
+		fileDest.Slack.Delivery = "file"
+		return postSlackResult(ctx, api, thread.Channel, thread.TS, t, fileDest, result, resultFilePath)
+	}
+	return nil
+}
+
+// sendToSlackWebhook posts the result through an incoming webhook. Webhooks
+// cannot upload files, so large results are summarized instead.
+func (e *Executor) sendToSlackWebhook(ctx context.Context, t *task.Task, dest destination.Destination, result QueryResult) error {
+	var blocks []slack.Block
+	if table, ok := slackTable(t, dest, result); ok {
+		blocks = slackTableBlocks(t, result, table)
+	} else {
+		blocks = slackSummaryBlocks(t, result)
+	}
+
+	if dest.LinkURL != "" {
+		link := expandTemplate(dest.LinkURL, t, result.Timestamp)
+		blocks = append(blocks, slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("<%s|View full result>", link), false, false), nil, nil))
+	}
+
+	msg := &slack.WebhookMessage{
+		Text:   t.Message,
+		Blocks: &slack.Blocks{BlockSet: blocks},
+	}
+	client, err := e.httpClient(dest)
+	if err != nil {
+		return err
+	}
+	if err := slack.PostWebhookCustomHTTPContext(ctx, dest.Token.Value, client, msg); err != nil {
+		return fmt.Errorf("failed to post to slack webhook: %w", err)
+	}
+	return nil
+}
+
+// slackTable renders the result as a table if it fits the destination limits
+func slackTable(t *task.Task, dest destination.Destination, result QueryResult) (string, bool) {
+	maxRows := dest.Slack.MaxTableRows
+	if maxRows <= 0 {
+		maxRows = defaultSlackMaxTableRows
+	}
+	maxChars := dest.Slack.MaxTableChars
+	if maxChars <= 0 || maxChars > defaultSlackMaxTableChars {
+		maxChars = defaultSlackMaxTableChars
+	}
+
+	if len(result.Data) > maxRows {
+		return "", false
+	}
+	table := renderTable(t, result, 0)
+	if utf8.RuneCountInString(table) > maxChars {
+		return "", false
+	}
+	return table, true
+}
+
+func slackSummaryBlocks(t *task.Task, result QueryResult) []slack.Block {
+	var blocks []slack.Block
+	if t.Message != "" {
+		blocks = append(blocks, slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, t.Message, false, false), nil, nil))
+	}
+	blocks = append(blocks,
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType,
+				fmt.Sprintf("Query returned *%d rows*, too many to show inline.", result.RowCount), false, false), nil, nil),
+		slackContextBlock(t, result),
+	)
+	return blocks
+}
+
+func slackTableBlocks(t *task.Task, result QueryResult, table string) []slack.Block {
+	var blocks []slack.Block
+	if t.Message != "" {
+		blocks = append(blocks, slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, t.Message, false, false), nil, nil))
+	}
+	blocks = append(blocks,
+		slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, "```\n"+table+"\n```", false, true), nil, nil),
+		slackContextBlock(t, result),
+	)
+	return blocks
+}
+
+func slackContextBlock(t *task.Task, result QueryResult) *slack.ContextBlock {
+	runTime := result.Timestamp.In(newValueFormatter(t).loc).Format(defaultTimestampLayout)
+	return slack.NewContextBlock("",
+		slack.NewTextBlockObject(slack.MarkdownType,
+			fmt.Sprintf("%s · %d rows · %s · %s", t.Name, result.RowCount, result.ExecutionTime, runTime), false, false))
+}
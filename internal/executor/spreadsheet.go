@@ -0,0 +1,134 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+const defaultSheetName = "Sheet1"
+
+// spreadsheetClient is implemented by each spreadsheet provider. Ranges use
+// A1 notation, e.g. "Sheet1" or "Sheet1!A1".
+type spreadsheetClient interface {
+	Get(ctx context.Context, rangeA1 string) ([][]interface{}, error)
+	Clear(ctx context.Context, rangeA1 string) error
+	Update(ctx context.Context, rangeA1 string, rows [][]interface{}) error
+	Append(ctx context.Context, rangeA1 string, rows [][]interface{}) error
+}
+
+func (e *Executor) sendToSpreadsheet(ctx context.Context, t *task.Task, dest destination.Destination, result QueryResult) error {
+	opts := dest.Spreadsheet
+	if opts.SpreadsheetID == "" {
+		return fmt.Errorf("spreadsheet destination requires a spreadsheet ID")
+	}
+
+	client, err := e.spreadsheetClient(ctx, dest)
+	if err != nil {
+		return err
+	}
+
+	sheet := opts.Sheet
+	if sheet == "" {
+		sheet = defaultSheetName
+	}
+	sheetRange := quoteSheetName(sheet)
+
+	switch opts.Mode {
+	case "", "replace":
+		if err := client.Clear(ctx, sheetRange); err != nil {
+			return err
+		}
+		rows := spreadsheetRows(t, result, !opts.SkipHeader)
+		if err := client.Update(ctx, sheetRange+"!A1", rows); err != nil {
+			return err
+		}
+
+	case "append":
+		// Only an empty sheet gets a header row
+		withHeader := false
+		if !opts.SkipHeader {
+			firstRow, err := client.Get(ctx, sheetRange+"!1:1")
+			if err != nil {
+				return err
+			}
+			withHeader = len(firstRow) == 0
+		}
+		rows := spreadsheetRows(t, result, withHeader)
+		if err := client.Append(ctx, sheetRange+"!A1", rows); err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("unsupported spreadsheet mode: %s", opts.Mode)
+	}
+
+	fmt.Printf("Wrote %d rows to spreadsheet %s (%s)\n", result.RowCount, opts.SpreadsheetID, sheet)
+	return nil
+}
+
+func (e *Executor) spreadsheetClient(ctx context.Context, dest destination.Destination) (spreadsheetClient, error) {
+	switch dest.Spreadsheet.Provider {
+	case "", "google":
+		return e.newGoogleSheetsClient(ctx, dest)
+	default:
+		return nil, fmt.Errorf("unsupported spreadsheet provider: %s", dest.Spreadsheet.Provider)
+	}
+}
+
+// spreadsheetRows returns the result as rows of cell values in column order
+func spreadsheetRows(t *task.Task, result QueryResult, withHeader bool) [][]interface{} {
+	headers := resultHeaders(result, t.Columns)
+	formatter := newValueFormatter(t)
+
+	rows := make([][]interface{}, 0, len(result.Data)+1)
+	if withHeader {
+		header := make([]interface{}, len(headers))
+		for i, h := range headers {
+			header[i] = h
+		}
+		rows = append(rows, header)
+	}
+
+	for _, row := range result.Data {
+		cells := make([]interface{}, len(headers))
+		for i, header := range headers {
+			cells[i] = spreadsheetValue(row[header], result.ColumnType(header), formatter)
+		}
+		rows = append(rows, cells)
+	}
+	return rows
+}
+
+// spreadsheetValue keeps numbers and booleans typed; everything else is text
+func spreadsheetValue(v interface{}, columnType string, formatter valueFormatter) interface{} {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case bool, int64:
+		return val
+	case float64, json.Number:
+		if n, ok := formatter.JSON(val, columnType).(json.Number); ok {
+			return n
+		}
+		return formatter.Text(val, columnType)
+	default:
+		return formatter.Text(val, columnType)
+	}
+}
+
+// quoteSheetName quotes a sheet name for use in A1 notation
+func quoteSheetName(name string) string {
+	quoted := "'"
+	for _, r := range name {
+		if r == '\'' {
+			quoted += "''"
+		} else {
+			quoted += string(r)
+		}
+	}
+	return quoted + "'"
+}
@@ -0,0 +1,55 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// slackThread remembers the Slack message a task posts into or updates
+type slackThread struct {
+	Mode    string `yaml:"mode"`           // daily or update
+	Date    string `yaml:"date,omitempty"` // day the daily thread was started
+	Channel string `yaml:"channel"`        // channel ID
+	TS      string `yaml:"ts"`             // message timestamp
+}
+
+func (e *Executor) slackThreadPath(taskName string) string {
+	return filepath.Join(e.stateDir, "state", "slack", taskName+".yaml")
+}
+
+// loadSlackThread returns the saved thread of a task, or nil if there is none
+func (e *Executor) loadSlackThread(taskName string) (*slackThread, error) {
+	data, err := os.ReadFile(e.slackThreadPath(taskName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read slack thread state: %w", err)
+	}
+
+	var thread slackThread
+	if err := yaml.Unmarshal(data, &thread); err != nil {
+		return nil, fmt.Errorf("failed to parse slack thread state: %w", err)
+	}
+	return &thread, nil
+}
+
+func (e *Executor) saveSlackThread(taskName string, thread *slackThread) error {
+	path := e.slackThreadPath(taskName)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(thread)
+	if err != nil {
+		return fmt.Errorf("failed to marshal slack thread state: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write slack thread state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package executor
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+var singleLine = strings.NewReplacer("\r\n", " ", "\n", " ", "\t", " ")
+
+// renderTable renders up to maxRows rows of the result as a plain text table
+// suitable for a monospace code block. A maxRows of 0 renders every row.
+func renderTable(t *task.Task, result QueryResult, maxRows int) string {
+	headers := resultHeaders(result, t.Columns)
+	formatter := newValueFormatter(t)
+
+	rows := result.Data
+	if maxRows > 0 && len(rows) > maxRows {
+		rows = rows[:maxRows]
+	}
+
+	cells := make([][]string, 0, len(rows)+1)
+	cells = append(cells, headers)
+	for _, row := range rows {
+		record := make([]string, len(headers))
+		for i, header := range headers {
+			value := formatter.Text(row[header], result.ColumnType(header))
+			// Keep every record on a single line
+			record[i] = singleLine.Replace(value)
+		}
+		cells = append(cells, record)
+	}
+
+	widths := make([]int, len(headers))
+	for _, record := range cells {
+		for i, value := range record {
+			if w := displayWidth(value); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for r, record := range cells {
+		for i, value := range record {
+			if i > 0 {
+				sb.WriteString(" | ")
+			}
+			sb.WriteString(value)
+			if i < len(record)-1 {
+				sb.WriteString(strings.Repeat(" ", widths[i]-displayWidth(value)))
+			}
+		}
+		sb.WriteString("\n")
+
+		// Separator below the header row
+		if r == 0 {
+			for i, w := range widths {
+				if i > 0 {
+					sb.WriteString("-+-")
+				}
+				sb.WriteString(strings.Repeat("-", w))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// displayWidth approximates the width of s in a monospace font, counting
+// East Asian wide characters as two columns
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if isWideRune(r) {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+func isWideRune(r rune) bool {
+	if r < utf8.RuneSelf {
+		return false
+	}
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303F) || // CJK symbols and punctuation
+		(r >= 0xFF01 && r <= 0xFF60) || // fullwidth forms
+		(r >= 0xFFE0 && r <= 0xFFE6)
+}
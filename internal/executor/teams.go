@@ -0,0 +1,151 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+const defaultTeamsMaxTableRows = 10
+
+type adaptiveCardMessage struct {
+	Type        string                   `json:"type"`
+	Attachments []adaptiveCardAttachment `json:"attachments"`
+}
+
+type adaptiveCardAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     adaptiveCard `json:"content"`
+}
+
+type adaptiveCard struct {
+	Schema  string                   `json:"$schema"`
+	Type    string                   `json:"type"`
+	Version string                   `json:"version"`
+	Body    []map[string]interface{} `json:"body"`
+	Actions []map[string]interface{} `json:"actions,omitempty"`
+	MSTeams map[string]interface{}   `json:"msteams,omitempty"`
+}
+
+func (e *Executor) sendToTeams(ctx context.Context, t *task.Task, dest destination.Destination, result QueryResult) error {
+	payload, err := json.Marshal(teamsMessage(t, dest, result))
+	if err != nil {
+		return fmt.Errorf("failed to encode teams message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", dest.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client, err := e.httpClient(dest)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("received non-success status code from teams: %d %s", resp.StatusCode, bytes.TrimSpace(body))
+		if retryableStatus(resp.StatusCode) {
+			return retryable(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// teamsMessage builds an Adaptive Card with the message, row count, the first
+// rows of the result as a table and an optional link to the full result
+func teamsMessage(t *task.Task, dest destination.Destination, result QueryResult) adaptiveCardMessage {
+	maxRows := dest.Teams.MaxTableRows
+	if maxRows <= 0 {
+		maxRows = defaultTeamsMaxTableRows
+	}
+
+	body := []map[string]interface{}{
+		{"type": "TextBlock", "text": t.Message, "wrap": true, "weight": "Bolder", "size": "Medium"},
+		{"type": "FactSet", "facts": []map[string]string{
+			{"title": "Task", "value": t.Name},
+			{"title": "Rows", "value": fmt.Sprintf("%d", result.RowCount)},
+			{"title": "Executed", "value": result.Timestamp.In(newValueFormatter(t).loc).Format(defaultTimestampLayout)},
+		}},
+		teamsTable(t, result, maxRows),
+	}
+	if result.RowCount > maxRows {
+		body = append(body, map[string]interface{}{
+			"type": "TextBlock", "text": fmt.Sprintf("Showing first %d of %d rows", maxRows, result.RowCount),
+			"isSubtle": true, "size": "Small", "wrap": true,
+		})
+	}
+
+	card := adaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.5",
+		Body:    body,
+		MSTeams: map[string]interface{}{"width": "Full"},
+	}
+	if dest.LinkURL != "" {
+		card.Actions = []map[string]interface{}{
+			{"type": "Action.OpenUrl", "title": "View full result", "url": expandTemplate(dest.LinkURL, t, result.Timestamp)},
+		}
+	}
+
+	return adaptiveCardMessage{
+		Type: "message",
+		Attachments: []adaptiveCardAttachment{
+			{ContentType: "application/vnd.microsoft.card.adaptive", Content: card},
+		},
+	}
+}
+
+func teamsTable(t *task.Task, result QueryResult, maxRows int) map[string]interface{} {
+	headers := resultHeaders(result, t.Columns)
+	formatter := newValueFormatter(t)
+
+	cell := func(text string, bold bool) map[string]interface{} {
+		block := map[string]interface{}{"type": "TextBlock", "text": text, "wrap": true}
+		if bold {
+			block["weight"] = "Bolder"
+		}
+		return map[string]interface{}{"type": "TableCell", "items": []map[string]interface{}{block}}
+	}
+
+	columns := make([]map[string]interface{}, len(headers))
+	headerCells := make([]map[string]interface{}, len(headers))
+	for i, header := range headers {
+		columns[i] = map[string]interface{}{"width": 1}
+		headerCells[i] = cell(header, true)
+	}
+
+	rows := []map[string]interface{}{{"type": "TableRow", "cells": headerCells}}
+	for r, row := range result.Data {
+		if r >= maxRows {
+			break
+		}
+		cells := make([]map[string]interface{}, len(headers))
+		for i, header := range headers {
+			cells[i] = cell(formatter.Text(row[header], result.ColumnType(header)), false)
+		}
+		rows = append(rows, map[string]interface{}{"type": "TableRow", "cells": cells})
+	}
+
+	return map[string]interface{}{
+		"type":             "Table",
+		"columns":          columns,
+		"rows":             rows,
+		"firstRowAsHeader": true,
+	}
+}
@@ -0,0 +1,134 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ONCALLJP/goractor/internal/destination"
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+func testResult(rows int) QueryResult {
+	result := QueryResult{
+		TaskID:        "daily_sales",
+		Timestamp:     time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC),
+		ExecutionTime: "10ms",
+		RowCount:      rows,
+		Columns:       []Column{{Name: "id", Type: "INT4"}, {Name: "name", Type: "TEXT"}},
+	}
+	for i := 1; i <= rows; i++ {
+		result.Data = append(result.Data, map[string]interface{}{
+			"id":   json.Number(fmt.Sprint(i)),
+			"name": fmt.Sprintf("row %d", i),
+		})
+	}
+	return result
+}
+
+func TestSendToTeams(t *testing.T) {
+	var got adaptiveCardMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %s, want application/json", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	e := &Executor{}
+	tk := &task.Task{Name: "daily_sales", Message: "Daily sales", Timezone: "UTC"}
+	dest := destination.Destination{
+		Type:    "teams",
+		URL:     server.URL,
+		LinkURL: "https://reports.example.com/{{task}}/{{date}}.csv",
+		Teams:   destination.TeamsOptions{MaxTableRows: 2},
+	}
+
+	if err := e.sendToTeams(context.Background(), tk, dest, testResult(3)); err != nil {
+		t.Fatalf("sendToTeams error: %v", err)
+	}
+
+	if len(got.Attachments) != 1 {
+		t.Fatalf("got %d attachments, want 1", len(got.Attachments))
+	}
+	card := got.Attachments[0].Content
+	if card.Type != "AdaptiveCard" {
+		t.Errorf("card type = %s", card.Type)
+	}
+
+	var table map[string]interface{}
+	var truncatedNote string
+	for _, block := range card.Body {
+		switch block["type"] {
+		case "Table":
+			table = block
+		case "TextBlock":
+			if text, _ := block["text"].(string); strings.HasPrefix(text, "Showing first") {
+				truncatedNote = text
+			}
+		}
+	}
+	if table == nil {
+		t.Fatal("card has no table")
+	}
+	// Header row plus MaxTableRows rows
+	if rows := table["rows"].([]interface{}); len(rows) != 3 {
+		t.Errorf("table has %d rows, want 3", len(rows))
+	}
+	if truncatedNote != "Showing first 2 of 3 rows" {
+		t.Errorf("truncation note = %q", truncatedNote)
+	}
+
+	if len(card.Actions) != 1 || card.Actions[0]["url"] != "https://reports.example.com/daily_sales/2024-01-02.csv" {
+		t.Errorf("actions = %v", card.Actions)
+	}
+}
+
+func TestSendToTeamsErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		retryable bool
+	}{
+		{name: "bad request", status: http.StatusBadRequest, retryable: false},
+		{name: "rate limited", status: http.StatusTooManyRequests, retryable: true},
+		{name: "server error", status: http.StatusBadGateway, retryable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(io.Discard, r.Body)
+				w.WriteHeader(tt.status)
+				w.Write([]byte("webhook says no"))
+			}))
+			defer server.Close()
+
+			e := &Executor{}
+			dest := destination.Destination{Type: "teams", URL: server.URL}
+			err := e.sendToTeams(context.Background(), &task.Task{Name: "t"}, dest, testResult(1))
+			if err == nil {
+				t.Fatal("sendToTeams succeeded, want error")
+			}
+			if !strings.Contains(err.Error(), "webhook says no") {
+				t.Errorf("error %q does not include the response body", err)
+			}
+			if isRetryable(err) != tt.retryable {
+				t.Errorf("isRetryable = %v, want %v", !tt.retryable, tt.retryable)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package executor
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+// expandTemplate fills the placeholders supported in destination paths and links:
+// {{task}}, {{date}} (2006-01-02), {{timestamp}} (20060102_150405) and {{ext}}
+// (the output format). Times are shown in the task's timezone.
+func expandTemplate(tmpl string, t *task.Task, runTime time.Time) string {
+	if loc, err := time.LoadLocation(t.Timezone); err == nil {
+		runTime = runTime.In(loc)
+	}
+
+	ext := t.OutputFormat
+	if ext == "" {
+		ext = "csv"
+	}
+
+	return strings.NewReplacer(
+		"{{task}}", t.Name,
+		"{{date}}", runTime.Format("2006-01-02"),
+		"{{timestamp}}", runTime.Format("20060102_150405"),
+		"{{ext}}", ext,
+	).Replace(tmpl)
+}
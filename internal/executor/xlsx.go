@@ -0,0 +1,181 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ONCALLJP/goractor/internal/task"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	xlsxSheetName   = "Result"
+	xlsxMinColWidth = 8
+	xlsxMaxColWidth = 80
+
+	// Excel keeps 15 significant digits; longer numbers are written as text
+	xlsxMaxDigits = 15
+)
+
+func (e *Executor) createXLSXFile(t *task.Task, result QueryResult) (string, error) {
+	headers := resultHeaders(result, t.Columns)
+	formatter := newValueFormatter(t)
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName("Sheet1", xlsxSheetName); err != nil {
+		return "", fmt.Errorf("failed to create sheet: %w", err)
+	}
+
+	headerStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return "", fmt.Errorf("failed to create header style: %w", err)
+	}
+	dateFormat := "yyyy-mm-dd"
+	dateStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	if err != nil {
+		return "", fmt.Errorf("failed to create date style: %w", err)
+	}
+	timestampFormat := "yyyy-mm-dd hh:mm:ss"
+	timestampStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &timestampFormat})
+	if err != nil {
+		return "", fmt.Errorf("failed to create timestamp style: %w", err)
+	}
+
+	widths := make([]int, len(headers))
+
+	// Write headers
+	for i, header := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		if err := f.SetCellValue(xlsxSheetName, cell, header); err != nil {
+			return "", fmt.Errorf("failed to write XLSX headers: %w", err)
+		}
+		widths[i] = utf8.RuneCountInString(header)
+	}
+	lastHeaderCell, _ := excelize.CoordinatesToCellName(len(headers), 1)
+	if err := f.SetCellStyle(xlsxSheetName, "A1", lastHeaderCell, headerStyle); err != nil {
+		return "", fmt.Errorf("failed to style XLSX headers: %w", err)
+	}
+
+	// Write data in the same order as headers
+	for r, row := range result.Data {
+		for i, header := range headers {
+			v := row[header]
+			if v == nil {
+				continue
+			}
+
+			columnType := result.ColumnType(header)
+			cell, _ := excelize.CoordinatesToCellName(i+1, r+2)
+			value := xlsxValue(v, columnType, formatter)
+			if err := f.SetCellValue(xlsxSheetName, cell, value); err != nil {
+				return "", fmt.Errorf("failed to write XLSX record: %w", err)
+			}
+
+			display := formatter.Text(v, columnType)
+			if _, ok := value.(time.Time); ok {
+				style := timestampStyle
+				if columnType == "DATE" {
+					style = dateStyle
+				}
+				if err := f.SetCellStyle(xlsxSheetName, cell, cell, style); err != nil {
+					return "", fmt.Errorf("failed to style XLSX record: %w", err)
+				}
+			}
+			if n := utf8.RuneCountInString(display); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	// Size columns to their content
+	for i, width := range widths {
+		name, _ := excelize.ColumnNumberToName(i + 1)
+		if err := f.SetColWidth(xlsxSheetName, name, name, xlsxColumnWidth(width)); err != nil {
+			return "", fmt.Errorf("failed to set XLSX column width: %w", err)
+		}
+	}
+
+	// Keep the header row visible while scrolling
+	if err := f.SetPanes(xlsxSheetName, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return "", fmt.Errorf("failed to freeze XLSX header: %w", err)
+	}
+
+	tmpDir := filepath.Join(os.TempDir(), "goractor")
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	timestamp := time.Now().Format("20060102_150405")
+	filename := filepath.Join(tmpDir, fmt.Sprintf("%s_%s.xlsx", result.TaskID, timestamp))
+	if err := f.SaveAs(filename); err != nil {
+		return "", fmt.Errorf("failed to save XLSX file: %w", err)
+	}
+
+	return filename, nil
+}
+
+// xlsxValue converts a scanned value into a typed cell value based on its column type
+func xlsxValue(v interface{}, columnType string, formatter valueFormatter) interface{} {
+	switch val := v.(type) {
+	case time.Time:
+		switch columnType {
+		case "TIMESTAMPTZ":
+			// Excel has no time zones, so cells hold the wall clock of the task time zone
+			return val.In(formatter.loc)
+		case "TIME", "TIMETZ":
+			return formatter.Text(val, columnType)
+		}
+		return val
+	case json.Number:
+		text := formatter.Text(val, columnType)
+		if significantDigits(text) > xlsxMaxDigits {
+			return text
+		}
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return f
+		}
+		return text
+	case int64, float64, bool, string:
+		return val
+	default:
+		return formatter.Text(val, columnType)
+	}
+}
+
+// significantDigits counts the digits of a decimal number that a float must
+// keep, ignoring the sign, leading zeros and trailing fractional zeros
+func significantDigits(number string) int {
+	if i := strings.IndexAny(number, "eE"); i >= 0 {
+		number = number[:i]
+	}
+	number = strings.TrimLeft(number, "+-")
+	if strings.Contains(number, ".") {
+		number = strings.TrimRight(number, "0")
+	}
+	number = strings.TrimLeft(strings.Replace(number, ".", "", 1), "0")
+	return len(number)
+}
+
+func xlsxColumnWidth(chars int) float64 {
+	width := float64(chars) + 2
+	if width < xlsxMinColWidth {
+		return xlsxMinColWidth
+	}
+	if width > xlsxMaxColWidth {
+		return xlsxMaxColWidth
+	}
+	return width
+}
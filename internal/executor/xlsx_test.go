@@ -0,0 +1,33 @@
+package executor
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ONCALLJP/goractor/internal/task"
+)
+
+func TestXLSXValueNumeric(t *testing.T) {
+	tests := []struct {
+		input string
+		want  interface{}
+	}{
+		{input: "42", want: float64(42)},
+		{input: "-1234.50", want: -1234.5},
+		{input: "0.000123", want: 0.000123},
+		{input: "123456789012345", want: float64(123456789012345)},
+		{input: "1234567890123456", want: "1234567890123456"},
+		{input: "9007199254740993", want: "9007199254740993"},
+		{input: "12345678901234.56", want: "12345678901234.56"},
+		{input: "-0.1234567890123456", want: "-0.1234567890123456"},
+		{input: "1.100000000000000000", want: 1.1},
+	}
+
+	formatter := newValueFormatter(&task.Task{Timezone: "UTC"})
+	for _, tt := range tests {
+		got := xlsxValue(json.Number(tt.input), "NUMERIC", formatter)
+		if got != tt.want {
+			t.Errorf("xlsxValue(%s) = %#v, want %#v", tt.input, got, tt.want)
+		}
+	}
+}
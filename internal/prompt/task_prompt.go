@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/ONCALLJP/goractor/internal/config"
 	"github.com/ONCALLJP/goractor/internal/destination"
@@ -111,15 +112,33 @@ func (p *TaskPrompt) promptBasicInfo(defaultValues *task.Task) (*task.Task, erro
 	}
 
 	// Output Format
+	formats := []string{"csv", "xlsx", "json"}
+	formatCursor := 0
+	if defaultValues != nil {
+		for i, format := range formats {
+			if format == defaultValues.OutputFormat {
+				formatCursor = i
+			}
+		}
+	}
 	formatPrompt := promptui.Select{
-		Label: "Output Format",
-		Items: []string{"csv"}, // TODO add more formats
+		Label:     "Output Format",
+		Items:     formats,
+		CursorPos: formatCursor,
 	}
 	_, outputFormat, err := formatPrompt.Run()
 	if err != nil {
 		return nil, fmt.Errorf("output format prompt failed: %w", err)
 	}
 
+	var csvOptions task.CSVOptions
+	if outputFormat == "csv" {
+		csvOptions, err = p.promptCSVOptions(defaultValues)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Destination
 	destinations := p.DestinationManager.List()
 	if len(destinations) == 0 {
@@ -136,6 +155,19 @@ func (p *TaskPrompt) promptBasicInfo(defaultValues *task.Task) (*task.Task, erro
 		return nil, fmt.Errorf("destination selection failed: %w", err)
 	}
 
+	// Keep the current options when there is nothing to ask, e.g. mention
+	// rules of a task posting through a Slack webhook
+	var slackOptions task.SlackOptions
+	if defaultValues != nil {
+		slackOptions = defaultValues.Slack
+	}
+	if dest, ok := p.DestinationManager.Get(destName); ok && dest.Type == "slack" && dest.Token.Type != "webhook" {
+		slackOptions, err = p.promptSlackOptions(defaultValues)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &task.Task{
 		Name:            name,
 		Database:        database,
@@ -146,9 +178,183 @@ func (p *TaskPrompt) promptBasicInfo(defaultValues *task.Task) (*task.Task, erro
 		Message:         message,
 		DestinationName: destName,
 		OutputFormat:    outputFormat,
+		CSV:             csvOptions,
+		Slack:           slackOptions,
 	}, nil
 }
 
+func (p *TaskPrompt) promptSlackOptions(defaultValues *task.Task) (task.SlackOptions, error) {
+	threadingModes := []string{"", "daily", "update"}
+
+	cursor := 0
+	if defaultValues != nil {
+		for i, mode := range threadingModes {
+			if mode == defaultValues.Slack.Threading {
+				cursor = i
+			}
+		}
+	}
+
+	threadingPrompt := promptui.Select{
+		Label: "Slack Posting",
+		Items: []string{
+			"new message every run",
+			"daily thread (first run of the day starts a thread)",
+			"update a pinned message",
+		},
+		CursorPos: cursor,
+	}
+	idx, _, err := threadingPrompt.Run()
+	if err != nil {
+		return task.SlackOptions{}, fmt.Errorf("slack posting prompt failed: %w", err)
+	}
+
+	opts := task.SlackOptions{Threading: threadingModes[idx]}
+	// Mention rules are configured in tasks.yaml, keep them when editing
+	if defaultValues != nil {
+		opts.Mentions = defaultValues.Slack.Mentions
+	}
+	return opts, nil
+}
+
+func (p *TaskPrompt) promptCSVOptions(defaultValues *task.Task) (task.CSVOptions, error) {
+	var defaults task.CSVOptions
+	if defaultValues != nil {
+		defaults = defaultValues.CSV
+	}
+
+	presets := []task.CSVOptions{
+		{},
+		{BOM: true, LineEnding: "crlf"},
+		{Encoding: "shift_jis", LineEnding: "crlf"},
+	}
+	presetCursor := len(presets)
+	for i, preset := range presets {
+		if preset == defaults {
+			presetCursor = i
+		}
+	}
+
+	presetPrompt := promptui.Select{
+		Label: "CSV Style",
+		Items: []string{
+			"default (UTF-8, comma, LF)",
+			"excel (UTF-8 with BOM, CRLF)",
+			"excel-ja (Shift_JIS, CRLF)",
+			"custom",
+		},
+		CursorPos: presetCursor,
+	}
+	idx, _, err := presetPrompt.Run()
+	if err != nil {
+		return task.CSVOptions{}, fmt.Errorf("CSV style prompt failed: %w", err)
+	}
+
+	if idx < len(presets) {
+		return presets[idx], nil
+	}
+
+	var opts task.CSVOptions
+
+	encodingCursor := 0
+	switch {
+	case defaults.BOM:
+		encodingCursor = 1
+	case defaults.Encoding == "shift_jis":
+		encodingCursor = 2
+	case defaults.Encoding == "cp932":
+		encodingCursor = 3
+	}
+	encodingPrompt := promptui.Select{
+		Label:     "Encoding",
+		Items:     []string{"utf-8", "utf-8 with BOM", "shift_jis", "cp932"},
+		CursorPos: encodingCursor,
+	}
+	_, encoding, err := encodingPrompt.Run()
+	if err != nil {
+		return task.CSVOptions{}, fmt.Errorf("encoding prompt failed: %w", err)
+	}
+	switch encoding {
+	case "utf-8 with BOM":
+		opts.BOM = true
+	case "shift_jis", "cp932":
+		opts.Encoding = encoding
+	}
+
+	defaultDelimiter := ","
+	if defaults.Delimiter != "" {
+		defaultDelimiter = defaults.Delimiter
+	}
+	delimiterPrompt := promptui.Prompt{
+		Label:     "Delimiter (single character or 'tab')",
+		Validate:  validateDelimiter,
+		AllowEdit: true,
+		Default:   defaultDelimiter,
+	}
+	delimiter, err := delimiterPrompt.Run()
+	if err != nil {
+		return task.CSVOptions{}, fmt.Errorf("delimiter prompt failed: %w", err)
+	}
+	if delimiter != "," {
+		opts.Delimiter = delimiter
+	}
+
+	lineEndingPrompt := promptui.Select{
+		Label:     "Line Ending",
+		Items:     []string{"lf", "crlf"},
+		CursorPos: boolCursor(defaults.LineEnding == "crlf"),
+	}
+	_, lineEnding, err := lineEndingPrompt.Run()
+	if err != nil {
+		return task.CSVOptions{}, fmt.Errorf("line ending prompt failed: %w", err)
+	}
+	if lineEnding == "crlf" {
+		opts.LineEnding = lineEnding
+	}
+
+	quotePrompt := promptui.Select{
+		Label:     "Quoting",
+		Items:     []string{"minimal", "all fields"},
+		CursorPos: boolCursor(defaults.QuoteAll),
+	}
+	_, quoting, err := quotePrompt.Run()
+	if err != nil {
+		return task.CSVOptions{}, fmt.Errorf("quoting prompt failed: %w", err)
+	}
+	opts.QuoteAll = quoting == "all fields"
+
+	nullPrompt := promptui.Prompt{
+		Label:     "NULL Representation (empty for blank)",
+		AllowEdit: true,
+		Default:   defaults.NullValue,
+	}
+	opts.NullValue, err = nullPrompt.Run()
+	if err != nil {
+		return task.CSVOptions{}, fmt.Errorf("NULL representation prompt failed: %w", err)
+	}
+
+	headerPrompt := promptui.Select{
+		Label:     "Header Row",
+		Items:     []string{"include", "omit"},
+		CursorPos: boolCursor(defaults.SkipHeader),
+	}
+	_, header, err := headerPrompt.Run()
+	if err != nil {
+		return task.CSVOptions{}, fmt.Errorf("header prompt failed: %w", err)
+	}
+	opts.SkipHeader = header == "omit"
+
+	return opts, nil
+}
+
+// boolCursor selects the second item of a two-way select when b is true
+func boolCursor(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 func (p *TaskPrompt) promptQuery(defaultValues *task.Task) (string, error) {
 	// Create temporary file
 	tmpfile, err := ioutil.TempFile("", "goractor-sql-*.sql")
@@ -404,6 +610,8 @@ func (p *TaskPrompt) EditTask(t *task.Task) error {
 	t.Message = updatedTask.Message
 	t.DestinationName = updatedTask.DestinationName
 	t.OutputFormat = updatedTask.OutputFormat
+	t.CSV = updatedTask.CSV
+	t.Slack = updatedTask.Slack
 
 	return nil
 }
@@ -424,6 +632,19 @@ func validateSelectSQL(input string) error {
 	return nil
 }
 
+func validateDelimiter(input string) error {
+	if input == "tab" || input == `\t` {
+		return nil
+	}
+	if utf8.RuneCountInString(input) != 1 {
+		return fmt.Errorf("delimiter must be a single character")
+	}
+	if input == `"` || input == "\r" || input == "\n" {
+		return fmt.Errorf("delimiter cannot be a quote or newline")
+	}
+	return nil
+}
+
 func validateTime(input string) error {
 	_, err := time.Parse("15:04", input)
 	return err
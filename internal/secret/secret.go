@@ -0,0 +1,261 @@
+package secret
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// referencePattern matches ${env:NAME} and ${file:/path/to/secret}
+var referencePattern = regexp.MustCompile(`\$\{(env|file):([^}]+)\}`)
+
+// HasReference reports whether s contains a secret reference
+func HasReference(s string) bool {
+	return referencePattern.MatchString(s)
+}
+
+// Expand replaces the secret references in s with their values. Trailing
+// newlines are stripped from file contents.
+func Expand(s string) (string, error) {
+	var expandErr error
+	expanded := referencePattern.ReplaceAllStringFunc(s, func(ref string) string {
+		match := referencePattern.FindStringSubmatch(ref)
+		kind, name := match[1], strings.TrimSpace(match[2])
+
+		switch kind {
+		case "env":
+			value, ok := os.LookupEnv(name)
+			if !ok && expandErr == nil {
+				expandErr = fmt.Errorf("environment variable %s is not set", name)
+			}
+			return value
+		default:
+			data, err := os.ReadFile(name)
+			if err != nil && expandErr == nil {
+				expandErr = fmt.Errorf("failed to read secret file: %w", err)
+			}
+			return strings.TrimRight(string(data), "\r\n")
+		}
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return expanded, nil
+}
+
+// Values records the references replaced by Resolve together with what they
+// resolved to, so MarshalYAML can write them back
+type Values struct {
+	fields []resolvedField
+}
+
+type resolvedField struct {
+	path     string // e.g. databases.db1.password
+	schema   string // path with map keys and indexes replaced, e.g. databases.*.password
+	ref      string // the value as written
+	resolved string
+}
+
+// lookup returns the reference to write for a field holding value. The field
+// at the same path is matched first; otherwise a non-empty value matches the
+// same field of another entry, so renamed and copied entries keep references.
+func (vals *Values) lookup(path, schema, value string) (string, bool) {
+	for _, field := range vals.fields {
+		if field.path == path && field.resolved == value {
+			return field.ref, true
+		}
+	}
+	if value == "" {
+		return "", false
+	}
+	for _, field := range vals.fields {
+		if field.schema == schema && field.resolved == value {
+			return field.ref, true
+		}
+	}
+	return "", false
+}
+
+// Resolve expands the secret references in every string field reachable
+// from v, which must be a pointer, and records them for MarshalYAML.
+// References that cannot be resolved, such as variables only set in the
+// systemd unit, are left in place; Check reports them where they are needed.
+func Resolve(v interface{}) *Values {
+	vals := &Values{}
+	resolveValue(reflect.ValueOf(v), "", "", vals)
+	return vals
+}
+
+func resolveValue(v reflect.Value, path, schema string, vals *Values) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			resolveValue(v.Elem(), path, schema, vals)
+		}
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.IsExported() {
+				name := fieldName(field)
+				resolveValue(v.Field(i), joinPath(path, name), joinPath(schema, name), vals)
+			}
+		}
+
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			resolveValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), schema+"[]", vals)
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			// Map values are not addressable, so resolve a copy and store it back
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			key := fmt.Sprint(iter.Key().Interface())
+			resolveValue(elem, joinPath(path, key), joinPath(schema, "*"), vals)
+			v.SetMapIndex(iter.Key(), elem)
+		}
+
+	case reflect.String:
+		if !v.CanSet() || !HasReference(v.String()) {
+			return
+		}
+		expanded, err := Expand(v.String())
+		if err != nil {
+			return
+		}
+		vals.fields = append(vals.fields, resolvedField{path: path, schema: schema, ref: v.String(), resolved: expanded})
+		v.SetString(expanded)
+	}
+}
+
+// Check returns an error naming the first field reachable from v that still
+// holds a reference Resolve could not resolve
+func Check(v interface{}) error {
+	return checkValue(reflect.ValueOf(v), "")
+}
+
+func checkValue(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return checkValue(v.Elem(), path)
+		}
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			if err := checkValue(v.Field(i), joinPath(path, fieldName(v.Type().Field(i)))); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := checkValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := checkValue(iter.Value(), joinPath(path, fmt.Sprint(iter.Key().Interface()))); err != nil {
+				return err
+			}
+		}
+
+	case reflect.String:
+		if !HasReference(v.String()) {
+			return nil
+		}
+		_, err := Expand(v.String())
+		if err == nil {
+			err = fmt.Errorf("secret became available after loading")
+		}
+		return fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	return nil
+}
+
+// MarshalYAML encodes v like yaml.Marshal, but writes back the reference of
+// every field that still holds the value recorded by Resolve. References are
+// not expanded again, so resolved secrets are never written to disk even if
+// the variable or file changed since, while edited values are.
+func MarshalYAML(v interface{}, vals *Values) ([]byte, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil || vals == nil || len(vals.fields) == 0 {
+		return data, err
+	}
+
+	// Work on a copy so the resolved values in use stay untouched
+	out := reflect.New(reflect.TypeOf(v).Elem())
+	if err := yaml.Unmarshal(data, out.Interface()); err != nil {
+		return nil, err
+	}
+	restoreValue(out.Elem(), "", "", vals)
+	return yaml.Marshal(out.Interface())
+}
+
+func restoreValue(v reflect.Value, path, schema string, vals *Values) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			restoreValue(v.Elem(), path, schema, vals)
+		}
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.IsExported() {
+				name := fieldName(field)
+				restoreValue(v.Field(i), joinPath(path, name), joinPath(schema, name), vals)
+			}
+		}
+
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			restoreValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), schema+"[]", vals)
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			key := fmt.Sprint(iter.Key().Interface())
+			restoreValue(elem, joinPath(path, key), joinPath(schema, "*"), vals)
+			v.SetMapIndex(iter.Key(), elem)
+		}
+
+	case reflect.String:
+		if !v.CanSet() {
+			return
+		}
+		if ref, ok := vals.lookup(path, schema, v.String()); ok {
+			v.SetString(ref)
+		}
+	}
+}
+
+func fieldName(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("yaml"), ",")[0]; name != "" {
+		return name
+	}
+	return field.Name
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,250 @@
+package secret
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type testOptions struct {
+	Token string   `yaml:"token"`
+	Hosts []string `yaml:"hosts"`
+}
+
+type testConfig struct {
+	Password string                 `yaml:"password"`
+	Plain    string                 `yaml:"plain"`
+	Options  *testOptions           `yaml:"options"`
+	Named    map[string]testOptions `yaml:"named"`
+}
+
+func secretFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExpand(t *testing.T) {
+	t.Setenv("GORACTOR_TEST_USER", "app")
+	file := secretFile(t, "from-file\r\n\n")
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "no reference", input: "plain $HOME {env:X}", want: "plain $HOME {env:X}"},
+		{name: "env", input: "${env:GORACTOR_TEST_USER}", want: "app"},
+		{name: "env with spaces", input: "${env: GORACTOR_TEST_USER }", want: "app"},
+		{name: "file trims newlines", input: "${file:" + file + "}", want: "from-file"},
+		{name: "embedded", input: "user=${env:GORACTOR_TEST_USER} pass=${file:" + file + "}", want: "user=app pass=from-file"},
+		{name: "missing env", input: "${env:GORACTOR_TEST_UNSET}", wantErr: true},
+		{name: "missing file", input: "${file:" + filepath.Join(t.TempDir(), "missing") + "}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Expand(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expand(%q) = %q, want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expand(%q) error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expand(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("GORACTOR_TEST_PASSWORD", "hunter2")
+	t.Setenv("GORACTOR_TEST_TOKEN", "xoxb-1")
+
+	cfg := &testConfig{
+		Password: "${env:GORACTOR_TEST_PASSWORD}",
+		Plain:    "unchanged",
+		Options:  &testOptions{Token: "${env:GORACTOR_TEST_TOKEN}", Hosts: []string{"a", "${env:GORACTOR_TEST_PASSWORD}"}},
+		Named:    map[string]testOptions{"slack": {Token: "Bearer ${env:GORACTOR_TEST_TOKEN}"}},
+	}
+	Resolve(cfg)
+
+	if cfg.Password != "hunter2" || cfg.Plain != "unchanged" {
+		t.Errorf("top level = %q, %q", cfg.Password, cfg.Plain)
+	}
+	if cfg.Options.Token != "xoxb-1" || cfg.Options.Hosts[1] != "hunter2" {
+		t.Errorf("pointer = %+v", cfg.Options)
+	}
+	if cfg.Named["slack"].Token != "Bearer xoxb-1" {
+		t.Errorf("map = %+v", cfg.Named)
+	}
+}
+
+func TestResolveLeavesUnavailableReferences(t *testing.T) {
+	t.Setenv("GORACTOR_TEST_TOKEN", "xoxb-1")
+
+	cfg := &testConfig{
+		Password: "${env:GORACTOR_TEST_UNSET}",
+		Named: map[string]testOptions{
+			"slack": {Token: "${env:GORACTOR_TEST_TOKEN}"},
+			"teams": {Token: "${file:/nonexistent/goractor-test}"},
+		},
+	}
+	Resolve(cfg)
+
+	if cfg.Password != "${env:GORACTOR_TEST_UNSET}" || cfg.Named["teams"].Token != "${file:/nonexistent/goractor-test}" {
+		t.Errorf("unavailable references changed: %+v", cfg)
+	}
+	if cfg.Named["slack"].Token != "xoxb-1" {
+		t.Errorf("available reference not resolved: %q", cfg.Named["slack"].Token)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Setenv("GORACTOR_TEST_TOKEN", "xoxb-1")
+
+	tests := []struct {
+		name    string
+		cfg     testConfig
+		wantErr string
+	}{
+		{name: "resolved", cfg: testConfig{Password: "${env:GORACTOR_TEST_TOKEN}"}},
+		{name: "plain values", cfg: testConfig{Password: "p", Options: &testOptions{Hosts: []string{"a"}}}},
+		{name: "missing env", cfg: testConfig{Password: "${env:GORACTOR_TEST_UNSET}"}, wantErr: "password"},
+		{name: "nested map", cfg: testConfig{Named: map[string]testOptions{"slack": {Token: "${env:GORACTOR_TEST_UNSET}"}}}, wantErr: "named.slack.token"},
+		{name: "slice", cfg: testConfig{Options: &testOptions{Hosts: []string{"a", "${env:GORACTOR_TEST_UNSET}"}}}, wantErr: "options.hosts[1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			Resolve(&cfg)
+			err := Check(cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Check error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Check succeeded, want error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "GORACTOR_TEST_UNSET is not set") {
+				t.Errorf("error %q does not name %s and the variable", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMarshalYAMLRoundTrip(t *testing.T) {
+	t.Setenv("GORACTOR_TEST_PASSWORD", "hunter2")
+	t.Setenv("GORACTOR_TEST_TOKEN", "xoxb-1")
+
+	cfg := &testConfig{
+		Password: "${env:GORACTOR_TEST_PASSWORD}",
+		Plain:    "unchanged",
+		Options:  &testOptions{Token: "${env:GORACTOR_TEST_TOKEN}", Hosts: []string{"${env:GORACTOR_TEST_PASSWORD}"}},
+		Named: map[string]testOptions{
+			"slack": {Token: "${env:GORACTOR_TEST_TOKEN}"},
+			"teams": {Token: "${env:GORACTOR_TEST_TOKEN}"},
+			"old":   {Token: "Bearer ${env:GORACTOR_TEST_TOKEN}"},
+		},
+	}
+	vals := Resolve(cfg)
+
+	// Edit one secret, rename an entry and add a new one
+	cfg.Named["teams"] = testOptions{Token: "new-token"}
+	cfg.Named["renamed"] = cfg.Named["old"]
+	delete(cfg.Named, "old")
+	cfg.Named["discord"] = testOptions{Token: "discord-token"}
+
+	// The secrets change after loading; the recorded values still match
+	t.Setenv("GORACTOR_TEST_PASSWORD", "rotated")
+	os.Unsetenv("GORACTOR_TEST_TOKEN")
+
+	out, err := MarshalYAML(cfg, vals)
+	if err != nil {
+		t.Fatalf("MarshalYAML error: %v", err)
+	}
+
+	var saved testConfig
+	if err := yaml.Unmarshal(out, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Password != "${env:GORACTOR_TEST_PASSWORD}" {
+		t.Errorf("password = %q, want the reference", saved.Password)
+	}
+	if saved.Options.Token != "${env:GORACTOR_TEST_TOKEN}" || saved.Options.Hosts[0] != "${env:GORACTOR_TEST_PASSWORD}" {
+		t.Errorf("options = %+v, want the references", saved.Options)
+	}
+	if saved.Named["slack"].Token != "${env:GORACTOR_TEST_TOKEN}" {
+		t.Errorf("slack token = %q, want the reference", saved.Named["slack"].Token)
+	}
+	if saved.Named["renamed"].Token != "Bearer ${env:GORACTOR_TEST_TOKEN}" {
+		t.Errorf("renamed token = %q, want the reference", saved.Named["renamed"].Token)
+	}
+	if saved.Named["teams"].Token != "new-token" {
+		t.Errorf("edited token = %q, want it written plain", saved.Named["teams"].Token)
+	}
+	if saved.Named["discord"].Token != "discord-token" {
+		t.Errorf("new token = %q", saved.Named["discord"].Token)
+	}
+	if strings.Contains(string(out), "hunter2") || strings.Contains(string(out), "xoxb-1") {
+		t.Errorf("resolved secret written to YAML:\n%s", out)
+	}
+
+	// The resolved values in use are left alone
+	if cfg.Password != "hunter2" {
+		t.Errorf("MarshalYAML modified its input: password = %q", cfg.Password)
+	}
+}
+
+func TestMarshalYAMLOtherFieldsKeepValues(t *testing.T) {
+	t.Setenv("GORACTOR_TEST_TOKEN", "shared")
+
+	cfg := &testConfig{Options: &testOptions{Token: "${env:GORACTOR_TEST_TOKEN}"}}
+	vals := Resolve(cfg)
+	// Equal to a secret, but in a field that never held a reference
+	cfg.Plain = "shared"
+
+	out, err := MarshalYAML(cfg, vals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved testConfig
+	if err := yaml.Unmarshal(out, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Plain != "shared" {
+		t.Errorf("plain = %q, want the value as set", saved.Plain)
+	}
+}
+
+func TestMarshalYAMLWithoutValues(t *testing.T) {
+	cfg := &testConfig{Password: "hunter2"}
+	want, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, vals := range []*Values{nil, {}} {
+		got, err := MarshalYAML(cfg, vals)
+		if err != nil {
+			t.Fatalf("MarshalYAML(%#v) error: %v", vals, err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("MarshalYAML(%#v) = %s, want %s", vals, got, want)
+		}
+	}
+}
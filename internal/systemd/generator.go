@@ -11,9 +11,15 @@ import (
 	"github.com/ONCALLJP/goractor/internal/task"
 )
 
+const (
+	outboxUnit          = "goractor-outbox"
+	outboxRetryInterval = "5min"
+)
+
 type ServiceGenerator struct {
-	projectDir string
-	serviceDir string
+	projectDir      string
+	serviceDir      string
+	environmentFile string
 }
 
 func NewServiceGenerator() *ServiceGenerator {
@@ -29,6 +35,20 @@ func NewServiceGenerator() *ServiceGenerator {
 	}
 }
 
+// SetEnvironmentFile makes the generated services load environment variables,
+// such as the secrets of ${env:...} references, from path
+func (g *ServiceGenerator) SetEnvironmentFile(path string) {
+	g.environmentFile = path
+}
+
+// environmentLine returns the EnvironmentFile directive, if configured
+func (g *ServiceGenerator) environmentLine() string {
+	if g.environmentFile == "" {
+		return ""
+	}
+	return fmt.Sprintf("EnvironmentFile=%s\n", g.environmentFile)
+}
+
 func (g *ServiceGenerator) GenerateService(t *task.Task) error {
 	// Generate service file
 	serviceContent := g.generateServiceFile(t)
@@ -38,9 +58,16 @@ func (g *ServiceGenerator) GenerateService(t *task.Task) error {
 	timerContent := g.generateTimerFile(t)
 	timerPath := filepath.Join(g.serviceDir, fmt.Sprintf("goractor-%s.timer", t.Name))
 
+	// The outbox timer is shared by all tasks and retries results that could
+	// not be delivered, independent of when each task runs next
+	outboxServicePath := filepath.Join(g.serviceDir, outboxUnit+".service")
+	outboxTimerPath := filepath.Join(g.serviceDir, outboxUnit+".timer")
+
 	// Create temporary files
 	tmpServicePath := filepath.Join(os.TempDir(), fmt.Sprintf("goractor-%s.service", t.Name))
 	tmpTimerPath := filepath.Join(os.TempDir(), fmt.Sprintf("goractor-%s.timer", t.Name))
+	tmpOutboxServicePath := filepath.Join(os.TempDir(), outboxUnit+".service")
+	tmpOutboxTimerPath := filepath.Join(os.TempDir(), outboxUnit+".timer")
 
 	// Write to temporary files first
 	if err := os.WriteFile(tmpServicePath, []byte(serviceContent), 0644); err != nil {
@@ -49,14 +76,24 @@ func (g *ServiceGenerator) GenerateService(t *task.Task) error {
 	if err := os.WriteFile(tmpTimerPath, []byte(timerContent), 0644); err != nil {
 		return fmt.Errorf("failed to write temporary timer file: %w", err)
 	}
+	if err := os.WriteFile(tmpOutboxServicePath, []byte(g.generateOutboxServiceFile()), 0644); err != nil {
+		return fmt.Errorf("failed to write temporary outbox service file: %w", err)
+	}
+	if err := os.WriteFile(tmpOutboxTimerPath, []byte(g.generateOutboxTimerFile()), 0644); err != nil {
+		return fmt.Errorf("failed to write temporary outbox timer file: %w", err)
+	}
 
 	// Use sudo to move files and setup service
 	commands := [][]string{
 		{"mv", tmpServicePath, servicePath},
 		{"mv", tmpTimerPath, timerPath},
+		{"mv", tmpOutboxServicePath, outboxServicePath},
+		{"mv", tmpOutboxTimerPath, outboxTimerPath},
 		{"systemctl", "daemon-reload"},
 		{"systemctl", "enable", fmt.Sprintf("goractor-%s.timer", t.Name)},
 		{"systemctl", "start", fmt.Sprintf("goractor-%s.timer", t.Name)},
+		{"systemctl", "enable", outboxUnit + ".timer"},
+		{"systemctl", "start", outboxUnit + ".timer"},
 		{"touch", "/var/log/goractor.log", "/var/log/goractor.error.log"},
 		{"chown", os.Getenv("USER") + ":" + os.Getenv("USER"), "/var/log/goractor.log", "/var/log/goractor.error.log"},
 	}
@@ -91,12 +128,53 @@ ExecStart=%s task run %s
 User=%s
 Environment="HOME=%s"
 Environment="PATH=/usr/local/go/bin:/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
-StandardOutput=append:/var/log/goractor.log
+%sStandardOutput=append:/var/log/goractor.log
+StandardError=append:/var/log/goractor.error.log
+
+[Install]
+WantedBy=multi-user.target
+`, t.Name, homeDir, binaryPath, t.Name, currentUser, homeDir, g.environmentLine())
+}
+
+// generateOutboxServiceFile runs the due outbox retries of every task and
+// expires old items, including those of removed tasks
+func (g *ServiceGenerator) generateOutboxServiceFile() string {
+	currentUser := os.Getenv("USER")
+	homeDir := os.Getenv("HOME")
+	binaryPath := filepath.Join(homeDir, "go", "bin", "goractor")
+
+	return fmt.Sprintf(`[Unit]
+Description=Goractor outbox retry
+After=network.target
+
+[Service]
+Type=oneshot
+WorkingDirectory=%s/goractor
+ExecStart=%s outbox retry --due
+User=%s
+Environment="HOME=%s"
+Environment="PATH=/usr/local/go/bin:/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+%sStandardOutput=append:/var/log/goractor.log
 StandardError=append:/var/log/goractor.error.log
 
 [Install]
 WantedBy=multi-user.target
-`, t.Name, homeDir, binaryPath, t.Name, currentUser, homeDir)
+`, homeDir, binaryPath, currentUser, homeDir, g.environmentLine())
+}
+
+// generateOutboxTimerFile checks the outbox every few minutes; each item's
+// own backoff decides whether it is actually retried
+func (g *ServiceGenerator) generateOutboxTimerFile() string {
+	return fmt.Sprintf(`[Unit]
+Description=Timer for Goractor outbox retry
+
+[Timer]
+OnBootSec=1min
+OnUnitActiveSec=%s
+
+[Install]
+WantedBy=timers.target
+`, outboxRetryInterval)
 }
 
 func (g *ServiceGenerator) generateTimerFile(t *task.Task) string {
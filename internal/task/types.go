@@ -43,15 +43,60 @@ type TokenConfig struct {
 }
 
 type Task struct {
-	Name            string   `yaml:"name"`
-	Database        string   `yaml:"database"` // reference to database config
-	Schedule        string   `yaml:"schedule"` // e.g., "every 1h", "daily 15:00"
-	Timezone        string   `yaml:"timezone"` // e.g., "Asia/Tokyo"
-	Query           string   `yaml:"query"`
-	Columns         []string `yaml:"columns"`
-	Message         string   `yaml:"message"`
-	DestinationName string   `yaml:"destination"`
-	OutputFormat    string   `yaml:"output_format"` // "json" or "csv"
+	Name            string        `yaml:"name"`
+	Database        string        `yaml:"database"` // reference to database config
+	Schedule        string        `yaml:"schedule"` // e.g., "every 1h", "daily 15:00"
+	Timezone        string        `yaml:"timezone"` // e.g., "Asia/Tokyo"
+	Query           string        `yaml:"query"`
+	Columns         []string      `yaml:"columns"`
+	Message         string        `yaml:"message"`
+	DestinationName string        `yaml:"destination"`
+	OutputFormat    string        `yaml:"output_format"` // "json", "csv" or "xlsx"
+	CSV             CSVOptions    `yaml:"csv,omitempty"`
+	Format          FormatOptions `yaml:"format,omitempty"`
+	Slack           SlackOptions  `yaml:"slack,omitempty"`
+}
+
+// SlackOptions holds per-task settings for Slack destinations
+type SlackOptions struct {
+	// Threading controls how recurring runs are posted:
+	// "" posts a new message every run, "daily" posts the first run of the
+	// day as a parent message and later runs as thread replies, "update"
+	// keeps a single pinned message updated with the latest result.
+	Threading string `yaml:"threading,omitempty"`
+
+	// Mentions are prepended to the message when their conditions match
+	Mentions []MentionRule `yaml:"mentions,omitempty"`
+}
+
+// MentionRule mentions a user or group when the result matches. All set
+// conditions must match; a rule without conditions mentions on every run.
+type MentionRule struct {
+	Mention string `yaml:"mention"`            // e.g. "@oncall", "@here", "<@U0123456>"
+	MinRows int    `yaml:"min_rows,omitempty"` // mention when at least this many rows are returned
+	Column  string `yaml:"column,omitempty"`   // mention when any row's value in this column matches
+	Equals  string `yaml:"equals,omitempty"`   // exact value to match in Column
+	Matches string `yaml:"matches,omitempty"`  // regular expression to match in Column
+}
+
+// FormatOptions controls how values are rendered in the output.
+// Timestamps with time zone are shown in the task's Timezone.
+type FormatOptions struct {
+	TimestampLayout  string `yaml:"timestamp_layout,omitempty"`  // Go layout, default "2006-01-02 15:04:05"
+	DateLayout       string `yaml:"date_layout,omitempty"`       // Go layout, default "2006-01-02"
+	NumericPrecision *int   `yaml:"numeric_precision,omitempty"` // decimal places for numeric/float columns
+}
+
+// CSVOptions controls how CSV output is written. The zero value writes
+// UTF-8 with a header row, comma delimiters and LF line endings.
+type CSVOptions struct {
+	Encoding   string `yaml:"encoding,omitempty"`    // "utf-8" (default), "shift_jis" or "cp932"
+	BOM        bool   `yaml:"bom,omitempty"`         // prepend a UTF-8 byte order mark
+	Delimiter  string `yaml:"delimiter,omitempty"`   // e.g. ",", ";", "tab"
+	LineEnding string `yaml:"line_ending,omitempty"` // "lf" (default) or "crlf"
+	QuoteAll   bool   `yaml:"quote_all,omitempty"`
+	NullValue  string `yaml:"null_value,omitempty"` // written for NULL values, e.g. "NULL"
+	SkipHeader bool   `yaml:"skip_header,omitempty"`
 }
 
 func (t Task) String() string {